@@ -0,0 +1,288 @@
+package lazada
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrTokenRequired is returned when an api that needs an access token is called by a client without one
+var ErrTokenRequired = errors.New("lazada: an access token is required for this api call")
+
+// ErrUnknownAPI is returned by CallAPI for names that have not been registered
+var ErrUnknownAPI = errors.New("lazada: unknown api")
+
+// PayloadKind is how an api takes its parameters
+type PayloadKind int
+
+const (
+	// PayloadNone apis take all their parameters in the query string
+	PayloadNone PayloadKind = iota
+
+	// PayloadXML apis take an XML document in the payload form field, other parameters go in the query string
+	PayloadXML
+
+	// PayloadMultipart apis take a *FileUpload sent as a multipart form, other parameters go in the query string
+	PayloadMultipart
+)
+
+// API describes a call on the open platform
+type API struct {
+	// Name is the key the api is registered under, e.g. "GetProducts"
+	Name string
+
+	// Path is the path of the api without the rest prefix, e.g. "/products/get"
+	Path string
+
+	// Method is the http method, defaults to GET
+	Method string
+
+	// AuthRequired is set if the api needs a client access token
+	AuthRequired bool
+
+	// Payload is how the api takes its parameters
+	Payload PayloadKind
+}
+
+// apiDetails declares how the apis in apiNames are called.
+// Apis missing here are GET calls without an access token or payload.
+var apiDetails = map[string]API{
+	"ImageMigrate":  {Method: "POST", AuthRequired: true, Payload: PayloadXML},
+	"CreateProduct": {Method: "POST", AuthRequired: true, Payload: PayloadXML},
+	"UpdateProduct": {Method: "POST", AuthRequired: true, Payload: PayloadXML},
+
+	"SuggestCategory": {AuthRequired: true},
+
+	"UploadImage":      {Method: "POST", AuthRequired: true, Payload: PayloadMultipart},
+	"MigrateImages":    {Method: "POST", AuthRequired: true, Payload: PayloadXML},
+	"GetImageResponse": {AuthRequired: true},
+
+	"UpdatePriceQuantity": {Method: "POST", AuthRequired: true, Payload: PayloadXML},
+	"RemoveProduct":       {Method: "POST", AuthRequired: true},
+	"DeactivateProduct":   {Method: "POST", AuthRequired: true, Payload: PayloadXML},
+	"RemoveSKU":           {Method: "POST", AuthRequired: true},
+	"GetItem":             {AuthRequired: true},
+	"GetQCStatus":         {AuthRequired: true},
+
+	"GetOrders":             {AuthRequired: true},
+	"GetOrder":              {AuthRequired: true},
+	"GetOrderItems":         {AuthRequired: true},
+	"GetMultipleOrderItems": {AuthRequired: true},
+	"PackOrder":             {Method: "POST", AuthRequired: true},
+	"ReadyToShipOrder":      {Method: "POST", AuthRequired: true},
+	"CancelOrder":           {Method: "POST", AuthRequired: true},
+	"GetFailureReasons":     {AuthRequired: true},
+	"GetDocument":           {AuthRequired: true},
+	"GetAWBHTML":            {AuthRequired: true},
+	"GetAWBPDF":             {AuthRequired: true},
+}
+
+var (
+	apisMu sync.RWMutex
+
+	// apis is the registry used by CallAPI, it starts with the apis in apiNames
+	apis = builtinAPIs()
+
+	// apiPaths maps the path of every api in apis to its name
+	apiPaths = builtinAPIPaths()
+)
+
+func builtinAPIs() map[string]API {
+	out := make(map[string]API, len(apiNames))
+	for name, path := range apiNames {
+		api := apiDetails[name]
+		api.Name = name
+		api.Path = path
+		if api.Method == "" {
+			api.Method = "GET"
+		}
+		out[name] = api
+	}
+
+	return out
+}
+
+func builtinAPIPaths() map[string]string {
+	out := make(map[string]string, len(apiNames))
+	for name, path := range apiNames {
+		out[path] = name
+	}
+
+	return out
+}
+
+// RegisterAPI adds an api to the registry so it can be called with CallAPI.
+// The built in apis can't be replaced and every api needs a path of its own,
+// registering a name again replaces the api registered before, e.g. to fix its path.
+func RegisterAPI(api API) error {
+	if api.Name == "" {
+		return errors.New("api name is required")
+	}
+
+	if !strings.HasPrefix(api.Path, "/") {
+		return fmt.Errorf("api path %q must start with /", api.Path)
+	}
+
+	if api.Method == "" {
+		api.Method = "GET"
+	}
+	api.Method = strings.ToUpper(api.Method)
+
+	if _, ok := apiNames[api.Name]; ok {
+		return fmt.Errorf("api %q is built in and cant be replaced", api.Name)
+	}
+
+	apisMu.Lock()
+	defer apisMu.Unlock()
+
+	if name, ok := apiPaths[api.Path]; ok && name != api.Name {
+		return fmt.Errorf("api path %q is already registered as %q", api.Path, name)
+	}
+
+	if old, ok := apis[api.Name]; ok {
+		delete(apiPaths, old.Path)
+	}
+
+	apis[api.Name] = api
+	apiPaths[api.Path] = api.Name
+	return nil
+}
+
+// LookupAPI returns the registered api with the name
+func LookupAPI(name string) (API, bool) {
+	apisMu.RLock()
+	defer apisMu.RUnlock()
+
+	api, ok := apis[name]
+	return api, ok
+}
+
+// CallAPI calls a registered api and decodes the data of the response into v.
+// Params are encoded into the query string, they can be url.Values or a struct with url tags.
+// Payload is encoded to XML for PayloadXML apis, must be a *FileUpload for PayloadMultipart apis and nil for the others.
+// Like Do, if v is an io.Writer the whole response body is written to it.
+func (c *Client) CallAPI(ctx context.Context, name string, params, payload, v interface{}) (*LazadaResponse, error) {
+	api, ok := LookupAPI(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAPI, name)
+	}
+
+	if api.AuthRequired && !c.hasToken() {
+		return nil, ErrTokenRequired
+	}
+
+	_, isFile := payload.(*FileUpload)
+	if payload != nil && (api.Payload == PayloadNone || isFile != (api.Payload == PayloadMultipart)) {
+		return nil, fmt.Errorf("api %s does not take a %T payload", name, payload)
+	}
+
+	u, err := encodeParams(api.Path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	fetch := func(ctx context.Context, v interface{}) (*LazadaResponse, error) {
+		req, err := c.NewRequest(api.Method, u, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.Do(ctx, req, v)
+	}
+
+	// Responses of apis needing an access token belong to a seller so they are never cached
+	if _, isWriter := v.(io.Writer); c.cache != nil && api.Method == "GET" && !api.AuthRequired && !isWriter {
+		if ttl, ok := c.cache.ttl(name); ok {
+			query := ""
+			if i := strings.Index(u, "?"); i >= 0 {
+				query = u[i+1:]
+			}
+
+			key := cacheKey(c.cacheRegion(), name, query)
+			return c.cache.call(ctx, key, ttl, v, func(ctx context.Context) (*LazadaResponse, error) { return fetch(ctx, nil) })
+		}
+	}
+
+	return fetch(ctx, v)
+}
+
+// Execute calls any api on the open platform by its path, e.g. "/seller/get", for apis that have no method yet.
+// Params are the business parameters of the api, the system parameters and the signature are added by the client.
+// Payload is sent in the payload parameter, a string, []byte or json.RawMessage is sent as is
+// so it can hold JSON, any other value is encoded to XML.
+// Method must be GET or POST, GET calls send every parameter in the query string and POST calls in the body.
+//
+// The returned response has the undecoded data of the api, errors are returned as an ErrorResponse like any other call.
+func (c *Client) Execute(ctx context.Context, method, apiPath string, params url.Values, payload interface{}) (*LazadaResponse, error) {
+	method = strings.ToUpper(method)
+	if method != "GET" && method != "POST" {
+		return nil, fmt.Errorf("method %s is not supported, use GET or POST", method)
+	}
+
+	if !strings.HasPrefix(apiPath, "/") {
+		apiPath = "/" + apiPath
+	}
+
+	values := url.Values{}
+	for k, v := range params {
+		values[k] = v
+	}
+
+	if payload != nil {
+		p, err := encodePayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		values.Set("payload", p)
+	}
+
+	req, err := c.NewRequest(method, apiPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if method == "POST" {
+		req, err = newFormRequest(method, req.URL.String(), values)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		req.URL.RawQuery = values.Encode()
+	}
+
+	return c.Do(ctx, req, nil)
+}
+
+// encodePayload returns the payload parameter for Execute
+func encodePayload(payload interface{}) (string, error) {
+	switch p := payload.(type) {
+	case string:
+		return p, nil
+	case []byte:
+		return string(p), nil
+	case json.RawMessage:
+		return string(p), nil
+	}
+
+	return encodeXML(payload)
+}
+
+// encodeParams adds params to the query string of path
+func encodeParams(path string, params interface{}) (string, error) {
+	switch p := params.(type) {
+	case nil:
+		return path, nil
+	case url.Values:
+		if len(p) == 0 {
+			return path, nil
+		}
+		return path + "?" + p.Encode(), nil
+	}
+
+	return addOptions(path, params)
+}
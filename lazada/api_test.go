@@ -0,0 +1,176 @@
+package lazada
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registerTestAPI registers an api for the duration of the test
+func registerTestAPI(t *testing.T, api API) {
+	require.NoError(t, RegisterAPI(api))
+	t.Cleanup(func() {
+		apisMu.Lock()
+		defer apisMu.Unlock()
+
+		delete(apiPaths, apis[api.Name].Path)
+		delete(apis, api.Name)
+	})
+}
+
+func TestClient_CallAPI(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	registerTestAPI(t, API{Name: "GetSellerTest", Path: "/seller/get", AuthRequired: true})
+	api, ok := LookupAPI("GetSellerTest")
+	require.True(t, ok)
+	assert.Equal(t, "GET", api.Method)
+
+	mux.HandleFunc("/rest/seller/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "x", r.URL.Query().Get("filter"))
+		assert.Equal(t, "faketoken", r.URL.Query().Get("access_token"))
+
+		sign := r.URL.Query().Get("sign")
+		q := r.URL.Query()
+		q.Del("sign")
+		assert.Equal(t, client.Signature("/seller/get", q, nil), sign)
+
+		fmt.Fprint(w, `{"code":"0","data":{"name":"test seller"}}`)
+	})
+
+	var seen string
+	client.Use(func(next CallHandler) CallHandler {
+		return func(call *Call) (*http.Response, *LazadaResponse, error) {
+			seen = call.API
+			return next(call)
+		}
+	})
+
+	seller := struct {
+		Name string `json:"name"`
+	}{}
+	_, err := client.CallAPI(context.Background(), "GetSellerTest", url.Values{"filter": {"x"}}, nil, &seller)
+	require.NoError(t, err)
+	assert.Equal(t, "test seller", seller.Name)
+	assert.Equal(t, "GetSellerTest", seen)
+}
+
+func TestClient_CallAPIErrors(t *testing.T) {
+	c, err := NewClient("123456", "testsecretnotarealsecret", Singapore)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = c.CallAPI(ctx, "NotAnAPI", nil, nil, nil)
+	assert.True(t, errors.Is(err, ErrUnknownAPI))
+
+	_, err = c.CallAPI(ctx, "GetOrders", nil, nil, nil)
+	assert.Equal(t, ErrTokenRequired, err)
+
+	_, err = c.NewTokenClient("token").CallAPI(ctx, "GetOrder", nil, struct{}{}, nil)
+	assert.Error(t, err)
+
+	assert.Error(t, RegisterAPI(API{Name: "Bad", Path: "no/slash"}))
+	assert.Error(t, RegisterAPI(API{Path: "/no/name"}))
+}
+
+func TestRegisterAPI(t *testing.T) {
+	// Built in apis can't be replaced
+	assert.Error(t, RegisterAPI(API{Name: "GetOrders", Path: "/orders/list"}))
+	api, ok := LookupAPI("GetOrders")
+	require.True(t, ok)
+	assert.Equal(t, "/orders/get", api.Path)
+
+	// Nor can their paths be taken by another name
+	assert.Error(t, RegisterAPI(API{Name: "ListOrdersTest", Path: "/orders/get"}))
+	assert.Equal(t, "GetOrders", apiName("/orders/get"))
+
+	// Registered apis can be replaced and are named by their new path
+	registerTestAPI(t, API{Name: "GetWarehouseTest", Path: "/warehouse/get"})
+	registerTestAPI(t, API{Name: "GetWarehouseTest", Path: "/warehouses/get"})
+	assert.Equal(t, "GetWarehouseTest", apiName("/warehouses/get"))
+	assert.Equal(t, "/warehouse/get", apiName("/warehouse/get"))
+
+	// Products.Get is called without an access token
+	api, ok = LookupAPI("GetProducts")
+	require.True(t, ok)
+	assert.False(t, api.AuthRequired)
+}
+
+func TestClient_Execute(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/promotion/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Empty(t, r.URL.RawQuery)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "summer", r.PostForm.Get("name"))
+		assert.Equal(t, `{"discount":10}`, r.PostForm.Get("payload"))
+
+		sign := r.PostForm.Get("sign")
+		r.PostForm.Del("sign")
+		assert.Equal(t, client.Signature("/promotion/create", r.PostForm, nil), sign)
+
+		fmt.Fprint(w, `{"code":"0","request_id":"req-1","data":{"id":7}}`)
+	})
+
+	mux.HandleFunc("/rest/promotion/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Contains(t, r.URL.Query().Get("payload"), "<Id>7</Id>")
+		fmt.Fprint(w, `{"code":"InvalidParameter","type":"ISV","message":"bad id"}`)
+	})
+
+	resp, err := client.Execute(context.Background(), "post", "/promotion/create",
+		url.Values{"name": {"summer"}}, json.RawMessage(`{"discount":10}`))
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", resp.RequestID)
+	assert.JSONEq(t, `{"id":7}`, string(resp.Data))
+
+	payload := struct {
+		XMLName xml.Name `xml:"Request"`
+		ID      int      `xml:"Id"`
+	}{ID: 7}
+	_, err = client.Execute(context.Background(), "GET", "promotion/get", nil, payload)
+	assert.True(t, errors.Is(err, ErrInvalidParameter))
+
+	_, err = client.Execute(context.Background(), "DELETE", "/promotion/get", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestClient_CallAPIParamsAndPayload(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	registerTestAPI(t, API{Name: "UpdateSellerTest", Path: "/seller/update", Method: "post",
+		AuthRequired: true, Payload: PayloadXML})
+
+	mux.HandleFunc("/rest/seller/update", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "1", r.PostForm.Get("seller_id"))
+		assert.NotEmpty(t, r.PostForm.Get("payload"))
+
+		// Query parameters are moved into the body so the signature covers them
+		sign := r.PostForm.Get("sign")
+		r.PostForm.Del("sign")
+		assert.Equal(t, client.Signature("/seller/update", r.PostForm, nil), sign)
+
+		fmt.Fprint(w, `{"code":"0","data":{}}`)
+	})
+
+	payload := struct {
+		XMLName xml.Name `xml:"Request"`
+		Name    string   `xml:"Seller>Name"`
+	}{Name: "test"}
+	_, err := client.CallAPI(context.Background(), "UpdateSellerTest", url.Values{"seller_id": {"1"}}, payload, nil)
+	require.NoError(t, err)
+}
@@ -28,7 +28,8 @@ type Token struct {
 	ExpiresIn        int `json:"expires_in"`
 	RefreshExpiresIn int `json:"refresh_expires_in"`
 
-	retrievedAt time.Time
+	// RetrievedAt is when the token was received from the open platform, the expiry times are relative to it
+	RetrievedAt time.Time `json:"retrieved_at"`
 }
 
 // ExpiresAt tells you the point in time when this token will expire
@@ -50,14 +51,14 @@ func (t *Token) Valid() bool {
 }
 
 func (t *Token) calculateExpires(exp int) time.Time {
-	return t.retrievedAt.Add(time.Second * time.Duration(exp))
+	return t.RetrievedAt.Add(time.Second * time.Duration(exp))
 }
 
 // AuthURL returns the URL you should use to start the OAuth flow
 // It takes in the URL that the user should be returned to as redirect
 // and a state variable which should be a random string
 func (a *AuthService) AuthURL(redirect, state string) string {
-	baseURL, _ := url.Parse("https://auth.lazada.com/oauth/authorize")
+	baseURL, _ := a.client.AuthBaseURL.Parse("oauth/authorize")
 
 	q := baseURL.Query()
 	q.Set("client_id", a.client.appKey)
@@ -72,7 +73,7 @@ func (a *AuthService) AuthURL(redirect, state string) string {
 // Exchange sends the received oauth code to the open platform and returns a token
 func (a *AuthService) Exchange(ctx context.Context, code string) (*Token, error) {
 	req, err := a.client.NewRequest("GET",
-		fmt.Sprintf("%s?code=%s", apiNames["AccessToken"], code), nil)
+		fmt.Sprintf("%srest%s?code=%s", a.client.AuthBaseURL, apiNames["AccessToken"], url.QueryEscape(code)), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -83,10 +84,12 @@ func (a *AuthService) Exchange(ctx context.Context, code string) (*Token, error)
 		return nil, err
 	}
 
-	t := &Token{retrievedAt: time.Now()}
+	t := &Token{}
 	if err := json.NewDecoder(&buf).Decode(t); err != nil {
 		return nil, errors.Wrap(err, "cant unmarshal token")
 	}
+	// Set after decoding so the response can't replace it
+	t.RetrievedAt = time.Now()
 
 	return t, nil
 }
@@ -94,7 +97,7 @@ func (a *AuthService) Exchange(ctx context.Context, code string) (*Token, error)
 // Refresh sends the refresh token and returns the new refreshed token
 func (a *AuthService) Refresh(ctx context.Context, token string) (*Token, error) {
 	req, err := a.client.NewRequest("GET",
-		fmt.Sprintf("%s?refresh_token=%s", apiNames["RefreshToken"], token), nil)
+		fmt.Sprintf("%srest%s?refresh_token=%s", a.client.AuthBaseURL, apiNames["RefreshToken"], url.QueryEscape(token)), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -105,10 +108,12 @@ func (a *AuthService) Refresh(ctx context.Context, token string) (*Token, error)
 		return nil, err
 	}
 
-	t := &Token{retrievedAt: time.Now()}
+	t := &Token{}
 	if err := json.NewDecoder(&buf).Decode(t); err != nil {
 		return nil, errors.Wrap(err, "cant unmarshal token")
 	}
+	// Set after decoding so the response can't replace it
+	t.RetrievedAt = time.Now()
 
 	return t, nil
 }
@@ -0,0 +1,298 @@
+package lazada
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// BrandQueryOptions pages through the brands returned by QueryBrands
+type BrandQueryOptions struct {
+	StartRow int `url:"startRow"`
+
+	// At most 200
+	PageSize int `url:"pageSize"`
+
+	// Only return brands whose name contains Name
+	Name string `url:"name,omitempty"`
+}
+
+// BrandPage is a page of brands returned by QueryBrands
+type BrandPage struct {
+	Brands   []*Brand `json:"module"`
+	Total    int      `json:"total_record"`
+	StartRow int      `json:"start_row"`
+	PageSize int      `json:"page_size"`
+}
+
+// QueryBrands returns a page of brands in the region set using the newer brand query api
+// which reports the total number of brands and can filter them by name
+func (p *ProductService) QueryBrands(ctx context.Context, opts *BrandQueryOptions) (*BrandPage, error) {
+	if opts == nil {
+		opts = &BrandQueryOptions{PageSize: DefaultListOptions.Limit}
+	}
+
+	page := &BrandPage{}
+	_, err := p.client.CallAPI(ctx, "QueryBrands", opts, nil, page)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Brands == nil {
+		page.Brands = []*Brand{}
+	}
+
+	return page, nil
+}
+
+// DefaultBrandWorkers is how many pages DownloadBrands fetches at once by default
+const DefaultBrandWorkers = 4
+
+// BrandDownloadOptions configures DownloadBrands
+type BrandDownloadOptions struct {
+	// How many pages are fetched at once, defaults to DefaultBrandWorkers
+	Workers int
+
+	// How many brands are fetched per call, defaults to DefaultListOptions.Limit
+	PageSize int
+
+	// Resume continues a download from the progress it returned or passed to OnProgress
+	Resume *BrandProgress
+
+	// OnProgress is called every time more brands have been downloaded, e.g. to save the progress.
+	// It is called from a single goroutine and must not modify the progress.
+	OnProgress func(*BrandProgress)
+}
+
+// BrandProgress is how far DownloadBrands got, it can be saved as JSON to resume the download later
+type BrandProgress struct {
+	// Offset of the first brand not downloaded yet
+	Offset int `json:"offset"`
+
+	Brands []*Brand `json:"brands"`
+
+	// Done is set once the whole catalog was downloaded
+	Done bool `json:"done"`
+}
+
+// DownloadBrands downloads every brand in the region set fetching several pages at once,
+// the download ends at the first empty page.
+// The progress is returned even if the download fails so it can be resumed with BrandDownloadOptions.Resume,
+// pages fetched after a page that failed are downloaded again when resuming.
+func (p *ProductService) DownloadBrands(ctx context.Context, opts *BrandDownloadOptions) (*BrandProgress, error) {
+	o := BrandDownloadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Workers <= 0 {
+		o.Workers = DefaultBrandWorkers
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultListOptions.Limit
+	}
+
+	progress := &BrandProgress{Brands: []*Brand{}}
+	if o.Resume != nil {
+		progress.Offset = o.Resume.Offset
+		progress.Brands = append(progress.Brands, o.Resume.Brands...)
+		progress.Done = o.Resume.Done
+	}
+
+	if progress.Done {
+		return progress, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type page struct {
+		offset int
+		limit  int
+	}
+
+	type result struct {
+		page
+		brands []*Brand
+		err    error
+	}
+
+	// At most Workers pages are in flight so the workers never block sending their result
+	jobs := make(chan page, o.Workers)
+	results := make(chan result, o.Workers)
+	defer close(jobs)
+
+	for i := 0; i < o.Workers; i++ {
+		go func() {
+			for pg := range jobs {
+				brands, err := p.Brands(ctx, &ListOptions{Offset: pg.offset, Limit: pg.limit})
+				results <- result{page: pg, brands: brands, err: err}
+			}
+		}()
+	}
+
+	next := progress.Offset
+	pending := map[int][]*Brand{}
+	// The rest of pages that came back short, the platform can return fewer brands than asked for
+	remainders := []page{}
+	inflight := 0
+	lastPage := false
+	var err error
+
+	for !progress.Done {
+		for err == nil && inflight < o.Workers && (len(remainders) > 0 || !lastPage) {
+			if len(remainders) > 0 {
+				jobs <- remainders[0]
+				remainders = remainders[1:]
+			} else {
+				jobs <- page{offset: next, limit: o.PageSize}
+				next += o.PageSize
+			}
+			inflight++
+		}
+
+		if inflight == 0 {
+			break
+		}
+
+		r := <-results
+		inflight--
+
+		if r.err != nil {
+			if err == nil {
+				err = r.err
+				cancel()
+			}
+			continue
+		}
+
+		// Only an empty page is the end of the catalog
+		if len(r.brands) == 0 {
+			lastPage = true
+		} else if len(r.brands) < r.limit {
+			remainders = append(remainders, page{offset: r.offset + len(r.brands), limit: r.limit - len(r.brands)})
+		}
+		pending[r.offset] = r.brands
+
+		// Pages can arrive out of order, progress only covers the brands downloaded without gaps
+		advanced := false
+		for brands, ok := pending[progress.Offset]; ok; brands, ok = pending[progress.Offset] {
+			delete(pending, progress.Offset)
+
+			if len(brands) == 0 {
+				progress.Done = true
+				break
+			}
+
+			progress.Brands = append(progress.Brands, brands...)
+			progress.Offset += len(brands)
+			advanced = true
+		}
+
+		if advanced && o.OnProgress != nil {
+			snapshot := *progress
+			snapshot.Brands = progress.Brands[:len(progress.Brands):len(progress.Brands)]
+			o.OnProgress(&snapshot)
+		}
+	}
+
+	return progress, err
+}
+
+// BrandIndex downloads every brand in the region set and indexes them, see DownloadBrands
+func (p *ProductService) BrandIndex(ctx context.Context) (*BrandIndex, error) {
+	progress, err := p.DownloadBrands(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBrandIndex(progress.Brands), nil
+}
+
+// BrandIndex finds brands by their name or global identifier without calling the api
+type BrandIndex struct {
+	brands []*Brand
+	exact  map[string]*Brand
+}
+
+// NewBrandIndex indexes the brands
+func NewBrandIndex(brands []*Brand) *BrandIndex {
+	ix := &BrandIndex{brands: brands, exact: make(map[string]*Brand, len(brands)*2)}
+
+	// Names take precedence over global identifiers, and the first brand with a name wins
+	for _, b := range brands {
+		if key := normalizeBrand(b.GlobalIdentifier); key != "" {
+			ix.exact[key] = b
+		}
+	}
+	for i := len(brands) - 1; i >= 0; i-- {
+		if key := normalizeBrand(brands[i].Name); key != "" {
+			ix.exact[key] = brands[i]
+		}
+	}
+
+	return ix
+}
+
+// Len returns the number of brands in the index
+func (ix *BrandIndex) Len() int {
+	return len(ix.brands)
+}
+
+// Lookup returns the brand whose name or global identifier is name ignoring case,
+// use the Name of the brand returned as the brand attribute of a product
+func (ix *BrandIndex) Lookup(name string) (*Brand, bool) {
+	b, ok := ix.exact[normalizeBrand(name)]
+	return b, ok
+}
+
+// Search returns at most limit brands matching the query by name or global identifier best first,
+// a limit of zero or less returns every match.
+// Exact matches come first, then prefixes, then names containing the query and then names with small typos.
+func (ix *BrandIndex) Search(query string, limit int) []*Brand {
+	query = normalizeBrand(query)
+	if query == "" {
+		return nil
+	}
+
+	type match struct {
+		b     *Brand
+		score int
+	}
+
+	matches := []match{}
+	for _, b := range ix.brands {
+		score := matchName(normalizeBrand(b.Name), query)
+		if gid := matchName(normalizeBrand(b.GlobalIdentifier), query); gid > score {
+			score = gid
+		}
+
+		if score > 0 {
+			matches = append(matches, match{b: b, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return utf8.RuneCountInString(matches[i].b.Name) < utf8.RuneCountInString(matches[j].b.Name)
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	out := make([]*Brand, len(matches))
+	for i, m := range matches {
+		out[i] = m.b
+	}
+
+	return out
+}
+
+// normalizeBrand normalizes a brand name or global identifier, identifiers use underscores between words
+func normalizeBrand(s string) string {
+	return normalizeName(strings.Replace(s, "_", " ", -1))
+}
@@ -0,0 +1,196 @@
+package lazada
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveBrands serves total brands from /brands/get at most maxRows at a time, failing the page at failOffset once.
+// A maxRows of zero returns as many brands as asked for.
+func serveBrands(t *testing.T, mux *http.ServeMux, total, failOffset, maxRows int) func() []int {
+	var mu sync.Mutex
+	offsets := []int{}
+	failed := false
+
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if maxRows > 0 && limit > maxRows {
+			limit = maxRows
+		}
+
+		mu.Lock()
+		offsets = append(offsets, offset)
+		fail := offset == failOffset && !failed
+		if fail {
+			failed = true
+		}
+		mu.Unlock()
+
+		if fail {
+			fmt.Fprint(w, `{"code":"ServiceTimeout","type":"ISP","message":"timeout"}`)
+			return
+		}
+
+		brands := []*Brand{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			brands = append(brands, &Brand{BrandID: i, Name: fmt.Sprintf("brand %d", i)})
+		}
+		data, _ := json.Marshal(brands)
+		fmt.Fprintf(w, `{"code":"0","data":%s}`, data)
+	})
+
+	return func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int{}, offsets...)
+	}
+}
+
+func TestProductService_DownloadBrands(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	serveBrands(t, mux, 95, -1, 0)
+
+	updates := 0
+	progress, err := client.Products.DownloadBrands(context.Background(), &BrandDownloadOptions{
+		Workers:    3,
+		PageSize:   10,
+		OnProgress: func(*BrandProgress) { updates++ },
+	})
+	require.NoError(t, err)
+	assert.True(t, progress.Done)
+	assert.Equal(t, 95, progress.Offset)
+	require.Len(t, progress.Brands, 95)
+	assert.True(t, updates > 0)
+
+	// Brands are in catalog order even though pages were fetched concurrently
+	for i, b := range progress.Brands {
+		assert.Equal(t, i, b.BrandID)
+	}
+}
+
+func TestProductService_DownloadBrands_CappedPages(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	// The platform returns fewer brands than asked for, short pages are not the end of the catalog
+	offsets := serveBrands(t, mux, 95, -1, 7)
+
+	progress, err := client.Products.DownloadBrands(context.Background(), &BrandDownloadOptions{Workers: 3, PageSize: 10})
+	require.NoError(t, err)
+	assert.True(t, progress.Done)
+	assert.Equal(t, 95, progress.Offset)
+	require.Len(t, progress.Brands, 95)
+	for i, b := range progress.Brands {
+		assert.Equal(t, i, b.BrandID)
+	}
+
+	// The rest of every short page is fetched on its own
+	assert.Contains(t, offsets(), 7)
+	assert.Contains(t, offsets(), 17)
+}
+
+func TestProductService_DownloadBrands_Resume(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	offsets := serveBrands(t, mux, 50, 20, 0)
+
+	ctx := context.Background()
+	opts := &BrandDownloadOptions{Workers: 1, PageSize: 10}
+	progress, err := client.Products.DownloadBrands(ctx, opts)
+	require.Error(t, err)
+	assert.False(t, progress.Done)
+	assert.Equal(t, 20, progress.Offset)
+	assert.Len(t, progress.Brands, 20)
+
+	// The progress survives being saved
+	data, err := json.Marshal(progress)
+	require.NoError(t, err)
+	saved := &BrandProgress{}
+	require.NoError(t, json.Unmarshal(data, saved))
+
+	opts.Resume = saved
+	progress, err = client.Products.DownloadBrands(ctx, opts)
+	require.NoError(t, err)
+	assert.True(t, progress.Done)
+	assert.Len(t, progress.Brands, 50)
+	// The failed page is fetched again, the empty page after a full one ends the download
+	assert.Equal(t, []int{0, 10, 20, 20, 30, 40, 50}, offsets())
+
+	// A finished download is not fetched again
+	progress, err = client.Products.DownloadBrands(ctx, &BrandDownloadOptions{Resume: progress})
+	require.NoError(t, err)
+	assert.Len(t, progress.Brands, 50)
+	assert.Len(t, offsets(), 7)
+}
+
+func TestProductService_QueryBrands(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/category/brands/query", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "20", r.URL.Query().Get("startRow"))
+		assert.Equal(t, "10", r.URL.Query().Get("pageSize"))
+		assert.Equal(t, "basix", r.URL.Query().Get("name"))
+		fmt.Fprint(w, `{"code":"0","data":{"module":[{"brand_id":1,"name":"Kid Basix","global_identifier":"kid_basix"}],
+			"total_record":21,"start_row":20,"page_size":10}}`)
+	})
+
+	page, err := client.Products.QueryBrands(context.Background(), &BrandQueryOptions{StartRow: 20, PageSize: 10, Name: "basix"})
+	require.NoError(t, err)
+	assert.Equal(t, 21, page.Total)
+	require.Len(t, page.Brands, 1)
+	assert.Equal(t, "kid_basix", page.Brands[0].GlobalIdentifier)
+}
+
+func TestBrandIndex(t *testing.T) {
+	ix := NewBrandIndex([]*Brand{
+		{BrandID: 1, Name: "Kid Basix", GlobalIdentifier: "kid_basix"},
+		{BrandID: 2, Name: "Kidz", GlobalIdentifier: "kidz"},
+		{BrandID: 3, Name: "Samsung", GlobalIdentifier: "samsung"},
+		{BrandID: 4, Name: "Samsonite", GlobalIdentifier: "samsonite_luggage"},
+		{BrandID: 5, Name: "No Brand", GlobalIdentifier: "kidz"},
+	})
+	assert.Equal(t, 5, ix.Len())
+
+	ids := func(bs []*Brand) []int {
+		out := []int{}
+		for _, b := range bs {
+			out = append(out, b.BrandID)
+		}
+		return out
+	}
+
+	b, ok := ix.Lookup("KID BASIX")
+	require.True(t, ok)
+	assert.Equal(t, 1, b.BrandID)
+
+	b, ok = ix.Lookup("samsonite_luggage")
+	require.True(t, ok)
+	assert.Equal(t, 4, b.BrandID)
+
+	// Names win over identifiers shared by another brand
+	b, _ = ix.Lookup("kidz")
+	assert.Equal(t, 2, b.BrandID)
+
+	_, ok = ix.Lookup("sams")
+	assert.False(t, ok)
+
+	assert.Equal(t, []int{3, 4}, ids(ix.Search("sams", 0)))
+	assert.Equal(t, []int{3}, ids(ix.Search("sams", 1)))
+	assert.Equal(t, []int{2, 5, 1}, ids(ix.Search("kid", 0)))
+	assert.Equal(t, []int{4}, ids(ix.Search("luggage", 0)))
+	assert.Equal(t, []int{3}, ids(ix.Search("samsong", 0)))
+	assert.Empty(t, ix.Search("", 0))
+}
@@ -0,0 +1,147 @@
+package lazada
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ProductBuilder builds a Product with typed setters for the common attributes.
+// Attributes specific to a category can still be set with Attr.
+//
+//	product := lazada.NewProductBuilder(10001958).
+//		Name("test product").
+//		Brand("Kid Basix").
+//		SKU("test-sku").Price(decimal.New(23, 0)).Quantity(1).Done().
+//		Build()
+type ProductBuilder struct {
+	product *Product
+}
+
+// NewProductBuilder starts a product in the primary category
+func NewProductBuilder(primaryCategory int) *ProductBuilder {
+	return &ProductBuilder{product: &Product{
+		PrimaryCategory: strconv.Itoa(primaryCategory),
+		Attributes:      &Attributes{Attrs: StringMap{}},
+	}}
+}
+
+// Attr sets any product attribute, use it for the attributes that have no setter
+func (b *ProductBuilder) Attr(name, value string) *ProductBuilder {
+	b.product.Attributes.Attrs[name] = value
+	return b
+}
+
+// Name sets the name of the product
+func (b *ProductBuilder) Name(name string) *ProductBuilder {
+	return b.Attr("name", name)
+}
+
+// Brand sets the brand of the product, it must be one of the brands returned by ProductService.Brands
+func (b *ProductBuilder) Brand(brand string) *ProductBuilder {
+	return b.Attr("brand", brand)
+}
+
+// Description sets the long description of the product
+func (b *ProductBuilder) Description(description string) *ProductBuilder {
+	return b.Attr("description", description)
+}
+
+// ShortDescription sets the highlights of the product
+func (b *ProductBuilder) ShortDescription(description string) *ProductBuilder {
+	return b.Attr("short_description", description)
+}
+
+// SKU adds a sku to the product and returns a builder for it, call Done to get back to the product
+func (b *ProductBuilder) SKU(sellerSKU string) *SKUBuilder {
+	sku := &Sku{SellerSku: sellerSKU, SkuAttrs: StringMap{}}
+	b.product.Skus = append(b.product.Skus, sku)
+
+	return &SKUBuilder{product: b, sku: sku}
+}
+
+// Build returns the product
+func (b *ProductBuilder) Build() *Product {
+	return b.product
+}
+
+// SKUBuilder sets the attributes of a sku added with ProductBuilder.SKU
+type SKUBuilder struct {
+	product *ProductBuilder
+	sku     *Sku
+}
+
+// Attr sets any sku attribute, use it for the attributes that have no setter
+func (b *SKUBuilder) Attr(name, value string) *SKUBuilder {
+	b.sku.SkuAttrs[name] = value
+	return b
+}
+
+// Price sets the price of the sku
+func (b *SKUBuilder) Price(price decimal.Decimal) *SKUBuilder {
+	return b.Attr("price", formatPrice(price))
+}
+
+// SpecialPrice sets the sale price of the sku
+// The dates are left out if they are zero
+func (b *SKUBuilder) SpecialPrice(price decimal.Decimal, from, to time.Time) *SKUBuilder {
+	b.Attr("special_price", formatPrice(price))
+	if !from.IsZero() {
+		b.Attr("special_from_date", from.Format(saleDateFormat))
+	}
+	if !to.IsZero() {
+		b.Attr("special_to_date", to.Format(saleDateFormat))
+	}
+
+	return b
+}
+
+// Quantity sets the stock of the sku
+func (b *SKUBuilder) Quantity(quantity int) *SKUBuilder {
+	return b.Attr("quantity", strconv.Itoa(quantity))
+}
+
+// ColorFamily sets the color of the sku
+func (b *SKUBuilder) ColorFamily(color string) *SKUBuilder {
+	return b.Attr("color_family", color)
+}
+
+// PackageWeight sets the weight of the package in kg
+func (b *SKUBuilder) PackageWeight(kg decimal.Decimal) *SKUBuilder {
+	return b.Attr("package_weight", kg.String())
+}
+
+// PackageDimensions sets the size of the package in cm
+func (b *SKUBuilder) PackageDimensions(length, width, height decimal.Decimal) *SKUBuilder {
+	b.Attr("package_length", length.String())
+	b.Attr("package_width", width.String())
+	return b.Attr("package_height", height.String())
+}
+
+// PackageContent sets what is in the box
+func (b *SKUBuilder) PackageContent(content string) *SKUBuilder {
+	return b.Attr("package_content", content)
+}
+
+// Images sets the image urls of the sku, they must be images on the Lazada platform, see ProductService.MigrateImage
+func (b *SKUBuilder) Images(urls ...string) *SKUBuilder {
+	b.sku.Images = &Images{Image: urls}
+	return b
+}
+
+// Done returns the builder of the product the sku belongs to
+func (b *SKUBuilder) Done() *ProductBuilder {
+	return b.product
+}
+
+// formatPrice formats a price with at least one decimal place, e.g. 23 as 23.0
+func formatPrice(price decimal.Decimal) string {
+	s := price.String()
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+
+	return s
+}
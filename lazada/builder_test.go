@@ -0,0 +1,94 @@
+package lazada
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductBuilder(t *testing.T) {
+	// The product from ExampleProductService_Create
+	want := &Product{
+		PrimaryCategory: "10001958",
+		Attributes: &Attributes{
+			Attrs: StringMap{
+				"name":               "test product creation",
+				"short_description":  "test product highlights",
+				"description":        "test product description",
+				"brand":              "Kid Basix",
+				"model":              "test model",
+				"recommended_gender": "Men",
+				"material":           "Cotton",
+				"waterproof":         "waterproof",
+				"warranty_type":      "Local (Singapore) manufacturer warranty",
+				"warranty":           "1 month",
+				"Hazmat":             "Battery, Flammable",
+			}},
+		Skus: []*Sku{
+			{
+				SellerSku: "test-product-creation-for-api",
+				Images:    &Images{Image: []string{"https://sg-live.slatic.net/original/b731a8098df7d606ab2e56efc650afcb.jpg"}},
+				SkuAttrs: StringMap{
+					"quantity":        "1",
+					"color_family":    "Black",
+					"special_price":   "0.0",
+					"price":           "23.0",
+					"package_length":  "1",
+					"package_weight":  "1",
+					"package_content": "test whats in the box",
+					"package_width":   "1",
+					"package_height":  "1",
+				},
+			},
+		},
+	}
+
+	one := decimal.New(1, 0)
+	got := NewProductBuilder(10001958).
+		Name("test product creation").
+		ShortDescription("test product highlights").
+		Description("test product description").
+		Brand("Kid Basix").
+		Attr("model", "test model").
+		Attr("recommended_gender", "Men").
+		Attr("material", "Cotton").
+		Attr("waterproof", "waterproof").
+		Attr("warranty_type", "Local (Singapore) manufacturer warranty").
+		Attr("warranty", "1 month").
+		Attr("Hazmat", "Battery, Flammable").
+		SKU("test-product-creation-for-api").
+		Images("https://sg-live.slatic.net/original/b731a8098df7d606ab2e56efc650afcb.jpg").
+		Quantity(1).
+		ColorFamily("Black").
+		SpecialPrice(decimal.Zero, time.Time{}, time.Time{}).
+		Price(decimal.New(23, 0)).
+		PackageDimensions(one, one, one).
+		PackageWeight(one).
+		PackageContent("test whats in the box").
+		Done().
+		Build()
+
+	assert.Equal(t, want, got)
+
+	wantXML, err := xml.Marshal(&ProductRequest{Product: want})
+	require.NoError(t, err)
+	gotXML, err := xml.Marshal(&ProductRequest{Product: got})
+	require.NoError(t, err)
+	assert.Equal(t, string(wantXML), string(gotXML))
+}
+
+func TestSKUBuilder_SpecialPrice(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	p := NewProductBuilder(1).
+		SKU("a").SpecialPrice(decimal.RequireFromString("19.90"), from, from.AddDate(0, 1, 0)).Done().
+		SKU("b").Price(decimal.RequireFromString("5.25")).Done().
+		Build()
+
+	require.Len(t, p.Skus, 2)
+	assert.Equal(t, StringMap{"special_price": "19.9", "special_from_date": "2024-03-01", "special_to_date": "2024-04-01"}, p.Skus[0].SkuAttrs)
+	assert.Equal(t, "5.25", p.Skus[1].SkuAttrs["price"])
+}
@@ -0,0 +1,398 @@
+package lazada
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCacheMiss is returned by a MetadataStore when there is no entry for the key or it expired
+var ErrCacheMiss = errors.New("lazada: cache miss")
+
+// DefaultMetadataTTL is how long the responses of the metadata apis are cached by a new MetadataCache
+const DefaultMetadataTTL = 24 * time.Hour
+
+// metadataFetchTimeout is how long a fetch shared by the callers missing an entry can take
+const metadataFetchTimeout = time.Minute
+
+// MetadataStore saves the responses cached by a MetadataCache
+type MetadataStore interface {
+	// Get returns the value saved for key or ErrCacheMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set saves the value for key until expires, replacing any previous value
+	Set(ctx context.Context, key string, value []byte, expires time.Time) error
+
+	// Delete removes the value for key, deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+
+	// DeletePrefix removes every value whose key starts with prefix, an empty prefix removes everything
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// MetadataCache caches the responses of slow changing apis such as brands, category trees and category attributes.
+// Responses are cached per region and parameters of the call, concurrent calls missing the same entry
+// share a single request.
+//
+// A cache is set on a client with WithMetadataCache and is shared by the clients created from it with NewTokenClient.
+type MetadataCache struct {
+	store MetadataStore
+
+	mu   sync.RWMutex
+	ttls map[string]time.Duration
+
+	flight       flightGroup
+	fetchTimeout time.Duration
+}
+
+// NewMetadataCache returns a cache saving to store that caches GetBrands, CategoryTree
+// and CategoryAttributes for DefaultMetadataTTL
+func NewMetadataCache(store MetadataStore) *MetadataCache {
+	return &MetadataCache{
+		store:        store,
+		fetchTimeout: metadataFetchTimeout,
+		ttls: map[string]time.Duration{
+			"GetBrands":          DefaultMetadataTTL,
+			"CategoryTree":       DefaultMetadataTTL,
+			"CategoryAttributes": DefaultMetadataTTL,
+		},
+	}
+}
+
+// SetTTL sets how long the responses of an api are cached, any GET api in the registry that doesn't need
+// an access token can be cached. A ttl of zero or less stops the api being cached.
+func (m *MetadataCache) SetTTL(api string, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ttl <= 0 {
+		delete(m.ttls, api)
+		return
+	}
+
+	m.ttls[api] = ttl
+}
+
+func (m *MetadataCache) ttl(api string) (time.Duration, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ttl, ok := m.ttls[api]
+	return ttl, ok
+}
+
+// Invalidate removes cached responses of the region.
+// If api is empty everything cached for the region is removed, otherwise only the responses of that api,
+// and if params are given only the response for those parameters.
+// Clients using a custom base url instead of a region cache their responses under the url.
+func (m *MetadataCache) Invalidate(ctx context.Context, region Region, api string, params url.Values) error {
+	if api == "" {
+		return m.store.DeletePrefix(ctx, string(region)+"|")
+	}
+
+	if params == nil {
+		return m.store.DeletePrefix(ctx, cacheKey(string(region), api, ""))
+	}
+
+	return m.store.Delete(ctx, cacheKey(string(region), api, params.Encode()))
+}
+
+// Purge removes every cached response
+func (m *MetadataCache) Purge(ctx context.Context) error {
+	return m.store.DeletePrefix(ctx, "")
+}
+
+// cacheKey is region|api|query, the query is sorted by url.Values.Encode so equal parameters give equal keys
+func cacheKey(region, api, query string) string {
+	return region + "|" + api + "|" + query
+}
+
+// call returns the cached data for key or fetches it, the data is decoded into v.
+// The fetch and saving its response run on the context of the flight so they finish for the other callers
+// if the caller that started them gives up, they are bounded by the fetch timeout instead.
+func (m *MetadataCache) call(ctx context.Context, key string, ttl time.Duration, v interface{}, fetch func(ctx context.Context) (*LazadaResponse, error)) (*LazadaResponse, error) {
+	var resp *LazadaResponse
+
+	// A broken store is treated as a miss, the cache should never make a call fail
+	data, err := m.store.Get(ctx, key)
+	if err == nil {
+		resp = &LazadaResponse{Code: "0", Data: data}
+	} else {
+		val, err := m.flight.do(ctx, key, m.fetchTimeout, func(ctx context.Context) (interface{}, error) {
+			resp, err := fetch(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			m.store.Set(ctx, key, resp.Data, time.Now().Add(ttl))
+			return resp, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Callers sharing a flight get their own copy
+		shared := *val.(*LazadaResponse)
+		resp = &shared
+	}
+
+	if v != nil {
+		if err := json.Unmarshal(resp.Data, v); err != nil {
+			return resp, errors.Wrap(err, "unable to unmarshal into struct")
+		}
+	}
+
+	return resp, nil
+}
+
+// cacheRegion is the region part of the cache keys of the client
+func (c *Client) cacheRegion() string {
+	if c.region != "" {
+		return string(c.region)
+	}
+
+	return c.BaseURL.String()
+}
+
+// MemoryMetadataStore keeps cached responses in memory and evicts the least recently used once full.
+// It is safe for concurrent use.
+type MemoryMetadataStore struct {
+	max int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryMetadataStore returns a store holding at most max entries, zero means no limit
+func NewMemoryMetadataStore(max int) *MemoryMetadataStore {
+	return &MemoryMetadataStore{
+		max:     max,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get implements MetadataStore
+func (s *MemoryMetadataStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+
+	e := el.Value.(*memoryEntry)
+	if time.Now().After(e.expires) {
+		s.remove(el)
+		return nil, ErrCacheMiss
+	}
+
+	s.order.MoveToFront(el)
+	return e.value, nil
+}
+
+// Set implements MetadataStore
+func (s *MemoryMetadataStore) Set(ctx context.Context, key string, value []byte, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.remove(el)
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryEntry{key: key, value: value, expires: expires})
+
+	if s.max > 0 && s.order.Len() > s.max {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+// Delete implements MetadataStore
+func (s *MemoryMetadataStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.remove(el)
+	}
+
+	return nil
+}
+
+// DeletePrefix implements MetadataStore
+func (s *MemoryMetadataStore) DeletePrefix(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, el := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			s.remove(el)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of entries in the store including expired ones not yet removed
+func (s *MemoryMetadataStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+func (s *MemoryMetadataStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.entries, el.Value.(*memoryEntry).key)
+}
+
+// FileMetadataStore keeps cached responses in a directory, one file per entry,
+// so they survive restarts. Files are written atomically and only readable by the current user.
+type FileMetadataStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type fileEntry struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// NewFileMetadataStore returns a store using the directory, it is created on the first Set
+func NewFileMetadataStore(dir string) *FileMetadataStore {
+	return &FileMetadataStore{dir: dir}
+}
+
+// Get implements MetadataStore
+func (s *FileMetadataStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, err := s.read(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Key != key || time.Now().After(e.Expires) {
+		os.Remove(s.path(key))
+		return nil, ErrCacheMiss
+	}
+
+	return e.Value, nil
+}
+
+// Set implements MetadataStore
+func (s *FileMetadataStore) Set(ctx context.Context, key string, value []byte, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(&fileEntry{Key: key, Expires: expires, Value: value})
+	if err != nil {
+		return errors.Wrap(err, "cant encode cache entry")
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return errors.Wrap(err, "cant create cache directory")
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, ".tmp")
+	if err != nil {
+		return errors.Wrap(err, "cant create cache file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "cant write cache file")
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "cant write cache file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), s.path(key)), "cant replace cache file")
+}
+
+// Delete implements MetadataStore
+func (s *FileMetadataStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "cant remove cache file")
+	}
+
+	return nil
+}
+
+// DeletePrefix implements MetadataStore
+func (s *FileMetadataStore) DeletePrefix(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return errors.Wrap(err, "cant list cache files")
+	}
+
+	for _, f := range files {
+		e, err := s.read(f)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		if err == nil && strings.HasPrefix(e.Key, prefix) {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "cant remove cache file")
+			}
+		}
+	}
+
+	return nil
+}
+
+// path is the file of a key, keys are hashed as they hold characters not allowed in file names
+func (s *FileMetadataStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *FileMetadataStore) read(path string) (*fileEntry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &fileEntry{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, errors.Wrap(err, "cant decode cache file")
+	}
+
+	return e, nil
+}
@@ -0,0 +1,275 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataCache(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	cache := NewMetadataCache(NewMemoryMetadataStore(0))
+	client.cache = cache
+	require.NoError(t, client.SetRegion(Singapore))
+
+	calls := map[string]int{}
+	mux.HandleFunc("/rest/category/attributes/get", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("primary_category_id")
+		calls[id]++
+		fmt.Fprintf(w, `{"code":"0","data":[{"name":"attr-%s"}]}`, id)
+	})
+
+	ctx := context.Background()
+	get := func(id int) string {
+		attrs, err := client.Products.CategoryAttributes(ctx, id)
+		require.NoError(t, err)
+		return attrs[0].Name
+	}
+
+	assert.Equal(t, "attr-1", get(1))
+	assert.Equal(t, "attr-1", get(1))
+	assert.Equal(t, "attr-2", get(2))
+	assert.Equal(t, map[string]int{"1": 1, "2": 1}, calls)
+
+	// Token clients share the cache
+	assert.Equal(t, "attr-1", get(1))
+	other := client.NewTokenClient("othertoken")
+	_, err := other.Products.CategoryAttributes(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"1": 1, "2": 1}, calls)
+
+	require.NoError(t, cache.Invalidate(ctx, Singapore, "CategoryAttributes", url.Values{"primary_category_id": {"1"}}))
+	get(1)
+	get(2)
+	assert.Equal(t, map[string]int{"1": 2, "2": 1}, calls)
+
+	require.NoError(t, cache.Invalidate(ctx, Singapore, "", nil))
+	get(1)
+	get(2)
+	assert.Equal(t, map[string]int{"1": 3, "2": 2}, calls)
+
+	// Other regions are cached separately
+	require.NoError(t, client.SetRegion(Malaysia))
+	get(1)
+	assert.Equal(t, 4, calls["1"])
+}
+
+func TestMetadataCache_SingleFlight(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.cache = NewMetadataCache(NewMemoryMetadataStore(0))
+
+	var calls int32
+	mux.HandleFunc("/rest/category/tree/get", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, `{"code":"0","data":[{"category_id":1,"name":"Fashion"}]}`)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tree, err := client.Products.CategoryTree(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, tree, 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestMetadataCache_SellerAPIsNotCached(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.cache = NewMetadataCache(NewMemoryMetadataStore(0))
+	client.cache.SetTTL("GetOrder", time.Hour)
+
+	calls := 0
+	mux.HandleFunc("/rest/order/get", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, `{"code":"0","data":{"order_id":1,"customer_first_name":"%s"}}`, r.URL.Query().Get("access_token"))
+	})
+
+	ctx := context.Background()
+	for _, seller := range []string{"seller1", "seller2", "seller1"} {
+		order, err := client.NewTokenClient(seller).Orders.GetOrder(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, seller, order.CustomerFirstName)
+	}
+	assert.Equal(t, 3, calls)
+}
+
+func TestMetadataCache_CallerCancelled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	store := NewMemoryMetadataStore(0)
+	client.cache = NewMetadataCache(store)
+
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux.HandleFunc("/rest/category/tree/get", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		fmt.Fprint(w, `{"code":"0","data":[{"category_id":1,"name":"Fashion"}]}`)
+	})
+
+	// The caller that started the fetch gives up while it is running
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.Products.CategoryTree(ctx)
+		first <- err
+	}()
+	<-started
+	cancel()
+	assert.Error(t, <-first)
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := client.Products.CategoryTree(context.Background())
+		second <- err
+	}()
+
+	close(release)
+	assert.NoError(t, <-second)
+	assert.Equal(t, 1, store.Len())
+
+	_, err := client.Products.CategoryTree(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestMetadataCache_HungFetch(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.cache = NewMetadataCache(NewMemoryMetadataStore(0))
+	client.cache.fetchTimeout = 50 * time.Millisecond
+
+	hung := make(chan struct{})
+	defer close(hung)
+
+	var calls int32
+	mux.HandleFunc("/rest/category/tree/get", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			select {
+			case <-hung:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		fmt.Fprint(w, `{"code":"0","data":[{"category_id":1,"name":"Fashion"}]}`)
+	})
+
+	_, err := client.Products.CategoryTree(context.Background())
+	assert.Error(t, err)
+
+	tree, err := client.Products.CategoryTree(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tree, 1)
+}
+
+func TestMetadataCache_ErrorsNotCached(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.cache = NewMetadataCache(NewMemoryMetadataStore(0))
+
+	calls := 0
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			fmt.Fprint(w, `{"code":"ServiceTimeout","type":"ISP","message":"timeout"}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":"0","data":[{"brand_id":1,"name":"Kid Basix"}]}`)
+	})
+
+	ctx := context.Background()
+	_, err := client.Products.Brands(ctx, nil)
+	require.Error(t, err)
+
+	for i := 0; i < 2; i++ {
+		brands, err := client.Products.Brands(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Kid Basix", brands[0].Name)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestMemoryMetadataStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryMetadataStore(2)
+	future := time.Now().Add(time.Hour)
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), future))
+	require.NoError(t, store.Set(ctx, "b", []byte("2"), future))
+
+	// Using a makes b the least recently used
+	_, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, store.Set(ctx, "c", []byte("3"), future))
+
+	_, err = store.Get(ctx, "b")
+	assert.Equal(t, ErrCacheMiss, err)
+	assert.Equal(t, 2, store.Len())
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Now().Add(-time.Second)))
+	_, err = store.Get(ctx, "a")
+	assert.Equal(t, ErrCacheMiss, err)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestFileMetadataStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazada")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	future := time.Now().Add(time.Hour)
+
+	store := NewFileMetadataStore(dir)
+	require.NoError(t, store.Set(ctx, "sg|CategoryTree|", []byte(`[1]`), future))
+	require.NoError(t, store.Set(ctx, "sg|GetBrands|limit=10", []byte(`[2]`), future))
+	require.NoError(t, store.Set(ctx, "my|CategoryTree|", []byte(`[3]`), future))
+	require.NoError(t, store.Set(ctx, "my|GetBrands|", []byte(`[4]`), time.Now().Add(-time.Second)))
+
+	// A new store reading the same directory sees the same entries
+	store = NewFileMetadataStore(dir)
+	v, err := store.Get(ctx, "sg|GetBrands|limit=10")
+	require.NoError(t, err)
+	assert.Equal(t, `[2]`, string(v))
+
+	_, err = store.Get(ctx, "my|GetBrands|")
+	assert.Equal(t, ErrCacheMiss, err)
+
+	require.NoError(t, store.DeletePrefix(ctx, "sg|"))
+	_, err = store.Get(ctx, "sg|CategoryTree|")
+	assert.Equal(t, ErrCacheMiss, err)
+
+	require.NoError(t, store.Delete(ctx, "my|CategoryTree|"))
+	require.NoError(t, store.Delete(ctx, "my|CategoryTree|"))
+	_, err = store.Get(ctx, "my|CategoryTree|")
+	assert.Equal(t, ErrCacheMiss, err)
+}
@@ -0,0 +1,274 @@
+package lazada
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// SkipChildren is returned by a CategoryWalkFunc to skip the children of a category
+var SkipChildren = errors.New("skip children")
+
+// CategoryWalkFunc is called by CategoryIndex.Walk for every category with the path of its parents, root first.
+// Returning SkipChildren skips the children of the category, any other error stops the walk and is returned by Walk.
+type CategoryWalkFunc func(c *CategoryTree, parents []*CategoryTree) error
+
+// CategoryIndex gives quick access to the categories of a tree returned by ProductService.CategoryTree
+type CategoryIndex struct {
+	roots  []*CategoryTree
+	byID   map[int]*CategoryTree
+	parent map[int]*CategoryTree
+}
+
+// NewCategoryIndex indexes the categories of the tree
+func NewCategoryIndex(tree []*CategoryTree) *CategoryIndex {
+	ix := &CategoryIndex{
+		roots:  tree,
+		byID:   make(map[int]*CategoryTree),
+		parent: make(map[int]*CategoryTree),
+	}
+
+	ix.Walk(func(c *CategoryTree, parents []*CategoryTree) error {
+		ix.byID[c.CategoryID] = c
+		if len(parents) > 0 {
+			ix.parent[c.CategoryID] = parents[len(parents)-1]
+		}
+		return nil
+	})
+
+	return ix
+}
+
+// CategoryIndex fetches the category tree of the region set and indexes it
+func (p *ProductService) CategoryIndex(ctx context.Context) (*CategoryIndex, error) {
+	tree, err := p.CategoryTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCategoryIndex(tree), nil
+}
+
+// ByID returns the category with the id
+func (ix *CategoryIndex) ByID(id int) (*CategoryTree, bool) {
+	c, ok := ix.byID[id]
+	return c, ok
+}
+
+// Path returns the categories from the root down to the category with the id, or nil if there is no such category
+func (ix *CategoryIndex) Path(id int) []*CategoryTree {
+	c, ok := ix.byID[id]
+	if !ok {
+		return nil
+	}
+
+	path := []*CategoryTree{c}
+	for p := ix.parent[c.CategoryID]; p != nil; p = ix.parent[p.CategoryID] {
+		path = append([]*CategoryTree{p}, path...)
+	}
+
+	return path
+}
+
+// Breadcrumb returns the names of the path to the category joined with " > "
+func (ix *CategoryIndex) Breadcrumb(id int) string {
+	path := ix.Path(id)
+
+	names := make([]string, len(path))
+	for i, c := range path {
+		names[i] = c.Name
+	}
+
+	return strings.Join(names, " > ")
+}
+
+// IsLeaf reports if the category exists and is a leaf, products can only be created in leaf categories
+func (ix *CategoryIndex) IsLeaf(id int) bool {
+	c, ok := ix.byID[id]
+	return ok && c.Leaf
+}
+
+// AcceptsVariants reports if products in the category can have more than one sku
+func (ix *CategoryIndex) AcceptsVariants(id int) bool {
+	c, ok := ix.byID[id]
+	return ok && c.Leaf && c.Var
+}
+
+// Leaves returns all the leaf categories in tree order
+func (ix *CategoryIndex) Leaves() []*CategoryTree {
+	leaves := []*CategoryTree{}
+	ix.Walk(func(c *CategoryTree, parents []*CategoryTree) error {
+		if c.Leaf {
+			leaves = append(leaves, c)
+		}
+		return nil
+	})
+
+	return leaves
+}
+
+// Walk calls fn for every category depth first in tree order
+func (ix *CategoryIndex) Walk(fn CategoryWalkFunc) error {
+	return walkCategories(ix.roots, nil, fn)
+}
+
+func walkCategories(tree []*CategoryTree, parents []*CategoryTree, fn CategoryWalkFunc) error {
+	for _, c := range tree {
+		err := fn(c, parents)
+		if err == SkipChildren {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Copy so fn can keep the parents it was given
+		path := append(parents[:len(parents):len(parents)], c)
+		if err := walkCategories(c.Children, path, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Scores of the ways a category name can match a search, higher is better
+const (
+	matchExact = 100 - iota*10
+	matchPrefix
+	matchContains
+	matchAllWords
+	matchFuzzyWords
+)
+
+// Search returns the categories whose name matches the query best first.
+// Names match if they contain the query or all its words, words with small typos still match.
+// Ties are broken by putting leaf categories first.
+func (ix *CategoryIndex) Search(query string) []*CategoryTree {
+	query = normalizeName(query)
+	if query == "" {
+		return nil
+	}
+
+	type match struct {
+		c     *CategoryTree
+		score int
+	}
+
+	matches := []match{}
+	ix.Walk(func(c *CategoryTree, parents []*CategoryTree) error {
+		if score := matchName(normalizeName(c.Name), query); score > 0 {
+			matches = append(matches, match{c: c, score: score})
+		}
+		return nil
+	})
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].c.Leaf && !matches[j].c.Leaf
+	})
+
+	out := make([]*CategoryTree, len(matches))
+	for i, m := range matches {
+		out[i] = m.c
+	}
+
+	return out
+}
+
+func normalizeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// matchName scores how well a normalized name matches a normalized query, 0 means no match
+func matchName(name, query string) int {
+	switch {
+	case name == query:
+		return matchExact
+	case strings.HasPrefix(name, query):
+		return matchPrefix
+	case strings.Contains(name, query):
+		return matchContains
+	}
+
+	words := strings.Fields(name)
+	all, fuzzy := true, true
+	for _, q := range strings.Fields(query) {
+		if !strings.Contains(name, q) {
+			all = false
+		}
+		if !hasSimilarWord(words, q) {
+			fuzzy = false
+		}
+	}
+
+	switch {
+	case all:
+		return matchAllWords
+	case fuzzy:
+		return matchFuzzyWords
+	}
+
+	return 0
+}
+
+// hasSimilarWord reports if one of the words is close enough to w to be a typo of it,
+// lengths and distances are counted in runes
+func hasSimilarWord(words []string, w string) bool {
+	n := utf8.RuneCountInString(w)
+
+	allowed := 0
+	switch {
+	case n >= 8:
+		allowed = 2
+	case n >= 4:
+		allowed = 1
+	}
+
+	for _, word := range words {
+		if levenshtein(word, w) <= allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+
+	return a
+}
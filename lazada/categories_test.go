@@ -0,0 +1,102 @@
+package lazada
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTree = []*CategoryTree{
+	{CategoryID: 1, Name: "Fashion", Children: []*CategoryTree{
+		{CategoryID: 2, Name: "Men", Children: []*CategoryTree{
+			{CategoryID: 3, Name: "T-Shirts", Leaf: true, Var: true},
+			{CategoryID: 4, Name: "Shoes", Leaf: true},
+		}},
+		{CategoryID: 5, Name: "Women Shoes", Leaf: true, Var: true},
+	}},
+	{CategoryID: 6, Name: "Electronics", Children: []*CategoryTree{
+		{CategoryID: 7, Name: "Mobile Phones", Leaf: true},
+		{CategoryID: 8, Name: "Phone Cases", Leaf: true},
+	}},
+}
+
+func TestCategoryIndex(t *testing.T) {
+	ix := NewCategoryIndex(testTree)
+
+	c, ok := ix.ByID(4)
+	require.True(t, ok)
+	assert.Equal(t, "Shoes", c.Name)
+
+	_, ok = ix.ByID(99)
+	assert.False(t, ok)
+	assert.Nil(t, ix.Path(99))
+
+	assert.Equal(t, "Fashion > Men > Shoes", ix.Breadcrumb(4))
+	assert.Len(t, ix.Path(1), 1)
+
+	var leaves []int
+	for _, l := range ix.Leaves() {
+		leaves = append(leaves, l.CategoryID)
+	}
+	assert.Equal(t, []int{3, 4, 5, 7, 8}, leaves)
+
+	assert.True(t, ix.IsLeaf(4))
+	assert.False(t, ix.IsLeaf(2))
+	assert.True(t, ix.AcceptsVariants(3))
+	assert.False(t, ix.AcceptsVariants(4))
+}
+
+func TestCategoryIndex_Walk(t *testing.T) {
+	ix := NewCategoryIndex(testTree)
+
+	var visited []int
+	err := ix.Walk(func(c *CategoryTree, parents []*CategoryTree) error {
+		visited = append(visited, c.CategoryID)
+		if c.CategoryID == 2 {
+			return SkipChildren
+		}
+		if c.CategoryID == 7 {
+			assert.Equal(t, 6, parents[0].CategoryID)
+			return errors.New("found")
+		}
+		return nil
+	})
+
+	assert.EqualError(t, err, "found")
+	assert.Equal(t, []int{1, 2, 5, 6, 7}, visited)
+}
+
+func TestCategoryIndex_Search(t *testing.T) {
+	ix := NewCategoryIndex(testTree)
+
+	ids := func(cs []*CategoryTree) []int {
+		out := []int{}
+		for _, c := range cs {
+			out = append(out, c.CategoryID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int{4, 5}, ids(ix.Search("shoes")))
+	// A prefix ranks above a match in the middle of the name
+	assert.Equal(t, []int{8, 7}, ids(ix.Search("phone")))
+	assert.Equal(t, []int{5}, ids(ix.Search("shoes women")))
+	assert.Equal(t, []int{7}, ids(ix.Search("mobil phnes")))
+	assert.Empty(t, ix.Search("garden"))
+	assert.Empty(t, ix.Search(" "))
+}
+
+func TestCategoryIndex_SearchNonASCII(t *testing.T) {
+	ix := NewCategoryIndex([]*CategoryTree{
+		{CategoryID: 1, Name: "Điện Thoại", Leaf: true},
+		{CategoryID: 2, Name: "Đèn", Leaf: true},
+	})
+
+	// One typo is allowed in words of four letters or more, however many bytes they take
+	matches := ix.Search("điên thoai")
+	require.Len(t, matches, 1)
+	assert.Equal(t, 1, matches[0].CategoryID)
+	assert.Empty(t, ix.Search("đèm"))
+}
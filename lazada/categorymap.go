@@ -0,0 +1,168 @@
+package lazada
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// CategorySuggestion is a category suggested for a product by SuggestCategories
+type CategorySuggestion struct {
+	CategoryID   int    `json:"categoryId"`
+	CategoryName string `json:"categoryName"`
+
+	// The names of the categories from the root down separated by ">"
+	CategoryPath string `json:"categoryPath"`
+}
+
+// SuggestCategories returns the categories of the region set that fit a product name best first
+// Requires a client access token
+func (p *ProductService) SuggestCategories(ctx context.Context, productName string) ([]*CategorySuggestion, error) {
+	params := url.Values{"product_name": {productName}}
+
+	resp := struct {
+		Suggestions []*CategorySuggestion `json:"categorySuggestions"`
+	}{}
+	_, err := p.client.CallAPI(ctx, "SuggestCategory", params, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Suggestions == nil {
+		return []*CategorySuggestion{}, nil
+	}
+
+	return resp.Suggestions, nil
+}
+
+// CategoryLink links a category of your own taxonomy to the Lazada category used for it in a region
+type CategoryLink struct {
+	Internal   string `json:"internal"`
+	Region     Region `json:"region"`
+	CategoryID int    `json:"category_id"`
+}
+
+// CategoryMap maps the categories of your own taxonomy to a Lazada category in each region,
+// category ids are different in every region.
+// The zero value is an empty map ready to use, it is safe for concurrent use and can be saved as JSON.
+type CategoryMap struct {
+	mu    sync.RWMutex
+	links map[string]map[Region]int
+}
+
+// LoadCategoryMap reads a map written by CategoryMap.Save
+func LoadCategoryMap(r io.Reader) (*CategoryMap, error) {
+	m := &CategoryMap{}
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return nil, errors.Wrap(err, "unable to load category map")
+	}
+
+	return m, nil
+}
+
+// Save writes the map as JSON
+func (m *CategoryMap) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return errors.Wrap(enc.Encode(m), "unable to save category map")
+}
+
+// Set links an internal category to a Lazada category in the region, replacing any previous link
+func (m *CategoryMap) Set(internal string, region Region, categoryID int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.links == nil {
+		m.links = make(map[string]map[Region]int)
+	}
+	if m.links[internal] == nil {
+		m.links[internal] = make(map[Region]int)
+	}
+
+	m.links[internal][region] = categoryID
+}
+
+// Lookup returns the Lazada category an internal category is linked to in the region
+func (m *CategoryMap) Lookup(internal string, region Region) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.links[internal][region]
+	return id, ok
+}
+
+// Delete removes the link of an internal category in the region
+func (m *CategoryMap) Delete(internal string, region Region) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.links[internal], region)
+	if len(m.links[internal]) == 0 {
+		delete(m.links, internal)
+	}
+}
+
+// Links returns all the links sorted by internal category and region
+func (m *CategoryMap) Links() []*CategoryLink {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	links := []*CategoryLink{}
+	for internal, regions := range m.links {
+		for region, id := range regions {
+			links = append(links, &CategoryLink{Internal: internal, Region: region, CategoryID: id})
+		}
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Internal != links[j].Internal {
+			return links[i].Internal < links[j].Internal
+		}
+		return links[i].Region < links[j].Region
+	})
+
+	return links
+}
+
+// Stale returns the links of the region whose category is missing from the index or is no longer a leaf.
+// Lazada reorganises its categories from time to time and products can only be created in leaf categories,
+// so check the map against a fresh CategoryTree of the region.
+func (m *CategoryMap) Stale(region Region, ix *CategoryIndex) []*CategoryLink {
+	stale := []*CategoryLink{}
+	for _, l := range m.Links() {
+		if l.Region == region && !ix.IsLeaf(l.CategoryID) {
+			stale = append(stale, l)
+		}
+	}
+
+	return stale
+}
+
+// MarshalJSON writes the map as a list of links
+func (m *CategoryMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Links())
+}
+
+// UnmarshalJSON replaces the links in the map with a list of links
+func (m *CategoryMap) UnmarshalJSON(data []byte) error {
+	links := []*CategoryLink{}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.links = nil
+	m.mu.Unlock()
+
+	for _, l := range links {
+		m.Set(l.Internal, l.Region, l.CategoryID)
+	}
+
+	return nil
+}
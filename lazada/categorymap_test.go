@@ -0,0 +1,62 @@
+package lazada
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductService_SuggestCategories(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/product/category/suggestion/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "red cotton shirt", r.URL.Query().Get("product_name"))
+		fmt.Fprint(w, `{"code":"0","data":{"categorySuggestions":[
+			{"categoryPath":"Fashion>Men>T-Shirts","categoryId":3,"categoryName":"T-Shirts"}]}}`)
+	})
+
+	suggestions, err := client.Products.SuggestCategories(context.Background(), "red cotton shirt")
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, &CategorySuggestion{CategoryID: 3, CategoryName: "T-Shirts", CategoryPath: "Fashion>Men>T-Shirts"}, suggestions[0])
+}
+
+func TestCategoryMap(t *testing.T) {
+	m := &CategoryMap{}
+	m.Set("apparel/shirts", Malaysia, 3)
+	m.Set("apparel/shirts", Singapore, 30)
+	m.Set("apparel/shoes", Malaysia, 2)
+	m.Set("apparel/gone", Malaysia, 99)
+
+	id, ok := m.Lookup("apparel/shirts", Singapore)
+	assert.True(t, ok)
+	assert.Equal(t, 30, id)
+
+	_, ok = m.Lookup("apparel/shoes", Singapore)
+	assert.False(t, ok)
+
+	// Men is no longer a leaf and 99 was removed
+	stale := m.Stale(Malaysia, NewCategoryIndex(testTree))
+	assert.Equal(t, []*CategoryLink{
+		{Internal: "apparel/gone", Region: Malaysia, CategoryID: 99},
+		{Internal: "apparel/shoes", Region: Malaysia, CategoryID: 2},
+	}, stale)
+
+	m.Delete("apparel/gone", Malaysia)
+	_, ok = m.Lookup("apparel/gone", Malaysia)
+	assert.False(t, ok)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, m.Save(buf))
+
+	loaded, err := LoadCategoryMap(buf)
+	require.NoError(t, err)
+	assert.Equal(t, m.Links(), loaded.Links())
+	assert.Len(t, loaded.Links(), 3)
+}
@@ -26,8 +26,32 @@ import (
 type Client struct {
 	BaseURL *url.URL
 
+	// AuthBaseURL is used for the token APIs and the OAuth authorize page
+	AuthBaseURL *url.URL
+
+	// region is the region set on the client, empty if a custom base url is used
+	region Region
+
+	// endpoints maps regions to their base url, it includes any custom endpoints
+	endpoints map[Region]string
+
 	client *http.Client
 
+	// timeout is applied to every call made through Do if greater than zero
+	timeout time.Duration
+
+	// retry decides if and when failed calls are attempted again, nil means never
+	retry *RetryPolicy
+
+	// limiter is waited on before every request is sent, it is shared with token clients
+	limiter RateLimiter
+
+	// middleware wraps every attempt of a call, see Use
+	middleware []Middleware
+
+	// cache holds the responses of the metadata apis, it is shared with token clients
+	cache *MetadataCache
+
 	common service
 
 	secret string
@@ -35,11 +59,17 @@ type Client struct {
 
 	accessToken string
 
+	// tokenSource supplies the access token instead of accessToken if set
+	tokenSource TokenSource
+
 	// The product service used for making API calls related to products
 	Products *ProductService
 
 	// The auth service used for making API calls related to authorization or OAuth
 	Auth *AuthService
+
+	// The order service used for making API calls related to orders
+	Orders *OrderService
 }
 
 type service struct {
@@ -75,39 +105,223 @@ type LazadaResponse struct {
 	Detail  []*ErrorDetails `json:"detail"`
 }
 
+// ClientOption configures optional settings on a client created with NewClient
+type ClientOption func(*Client) error
+
+// WithHTTPClient sets the http client used to make requests instead of http.DefaultClient
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) error {
+		c.client = hc
+		return nil
+	}
+}
+
+// WithTimeout limits how long each API call can take.
+// It applies on top of any deadline already set on the context passed to a call.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.timeout = d
+		return nil
+	}
+}
+
+// WithRetryPolicy makes the client retry failed calls according to the policy
+func WithRetryPolicy(p *RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.retry = p
+		return nil
+	}
+}
+
+// WithRateLimiter makes the client wait on the limiter before sending each request.
+// Clients created with NewTokenClient share the limiter so quotas are enforced across all of them.
+func WithRateLimiter(l RateLimiter) ClientOption {
+	return func(c *Client) error {
+		c.limiter = l
+		return nil
+	}
+}
+
+// WithMetadataCache caches the responses of slow changing apis such as Brands, CategoryTree and CategoryAttributes.
+// Clients created with NewTokenClient share the cache.
+func WithMetadataCache(m *MetadataCache) ClientOption {
+	return func(c *Client) error {
+		c.cache = m
+		return nil
+	}
+}
+
+// WithBaseURL sends API calls to rawurl instead of the region endpoint, e.g. a proxy or a mock
+func WithBaseURL(rawurl string) ClientOption {
+	return func(c *Client) error {
+		u, err := parseBaseURL(rawurl)
+		if err != nil {
+			return err
+		}
+
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithAuthURL sends the token APIs and the OAuth authorize page to rawurl instead of https://auth.lazada.com/
+func WithAuthURL(rawurl string) ClientOption {
+	return func(c *Client) error {
+		u, err := parseBaseURL(rawurl)
+		if err != nil {
+			return err
+		}
+
+		c.AuthBaseURL = u
+		return nil
+	}
+}
+
+// WithEndpoints adds or replaces region endpoints, e.g. to use sandbox or staging hosts.
+// The endpoints are used by NewClient and SetRegion.
+func WithEndpoints(eps map[Region]string) ClientOption {
+	return func(c *Client) error {
+		merged := make(map[Region]string, len(c.endpoints)+len(eps))
+		for r, u := range c.endpoints {
+			merged[r] = u
+		}
+
+		for r, u := range eps {
+			if _, err := parseBaseURL(u); err != nil {
+				return err
+			}
+			merged[r] = u
+		}
+
+		c.endpoints = merged
+		return nil
+	}
+}
+
 // NewClient takes in the application key, secret, and Lazada region and returns a client.
-func NewClient(appKey, secret string, region Region) *Client {
-	baseURL, _ := url.Parse(endpoints[region])
+// Any options are applied in order after the defaults are set.
+// An error is returned if an option fails or the region has no endpoint, unless a base url was set with WithBaseURL.
+func NewClient(appKey, secret string, region Region, opts ...ClientOption) (*Client, error) {
+	authURL, _ := url.Parse(defaultAuthURL)
 
 	c := &Client{
-		client:  http.DefaultClient,
-		appKey:  appKey,
-		secret:  secret,
-		BaseURL: baseURL,
+		client:      http.DefaultClient,
+		appKey:      appKey,
+		secret:      secret,
+		AuthBaseURL: authURL,
+		endpoints:   endpoints,
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.BaseURL == nil {
+		if err := c.SetRegion(region); err != nil {
+			return nil, err
+		}
 	}
 
 	initServices(c)
-	return c
+	return c, nil
 }
 
 func initServices(c *Client) {
 	c.common.client = c
 	c.Products = (*ProductService)(&c.common)
 	c.Auth = (*AuthService)(&c.common)
+	c.Orders = (*OrderService)(&c.common)
 }
 
 // NewTokenClient takes a client access token and returns a copy of the client with the token set.
 func (c *Client) NewTokenClient(token string) *Client {
 	newC := *c
 	newC.accessToken = token
+	newC.tokenSource = nil
 	initServices(&newC)
 	return &newC
 }
 
+// hasToken reports if calls made by the client will carry an access token
+func (c *Client) hasToken() bool {
+	return c.accessToken != "" || c.tokenSource != nil
+}
+
+// token returns the access token to use for a call to the api
+func (c *Client) token(ctx context.Context, api string) (string, error) {
+	// The token APIs dont take an access token, this also stops a token source refreshing through its own client
+	if c.tokenSource == nil || api == "AccessToken" || api == "RefreshToken" {
+		return c.accessToken, nil
+	}
+
+	t, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return t.AccessToken, nil
+}
+
 // SetRegion changes the region on the client
-func (c *Client) SetRegion(region Region) {
-	baseURL, _ := url.Parse(endpoints[region])
+// If the region has no endpoint ErrUnknownRegion is returned and the client is left unchanged.
+func (c *Client) SetRegion(region Region) error {
+	ep, ok := c.endpoints[region]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownRegion, region)
+	}
+
+	baseURL, err := parseBaseURL(ep)
+	if err != nil {
+		return err
+	}
+
 	c.BaseURL = baseURL
+	c.region = region
+	return nil
+}
+
+// Region returns the region the client is set to, it is empty if a custom base url is used
+func (c *Client) Region() Region {
+	return c.region
+}
+
+// SetBaseURL points the client at a URL other than the region endpoints, e.g. a proxy or a test server
+func (c *Client) SetBaseURL(rawurl string) error {
+	u, err := parseBaseURL(rawurl)
+	if err != nil {
+		return err
+	}
+
+	c.BaseURL = u
+	c.region = ""
+	return nil
+}
+
+// parseBaseURL parses a base url making sure it ends in a slash so paths can be resolved against it.
+// Both https://api.lazada.sg/ and https://api.lazada.sg/rest are accepted.
+func parseBaseURL(rawurl string) (*url.URL, error) {
+	if !strings.HasSuffix(rawurl, "/") {
+		rawurl += "/"
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, errors.Wrap(err, "cant parse base url")
+	}
+
+	if !u.IsAbs() {
+		return nil, errors.Errorf("base url %q is not absolute", rawurl)
+	}
+
+	// The client adds rest to every call so a base url that already ends with it would get it twice
+	if strings.HasSuffix(u.Path, "/rest/") {
+		u.Path = strings.TrimSuffix(u.Path, "rest/")
+		u.RawPath = ""
+	}
+
+	return u, nil
 }
 
 // addOptions sets the query string using the query encoding library
@@ -132,9 +346,10 @@ func addOptions(s string, opt interface{}) (string, error) {
 }
 
 // NewRequest returns an http request conforming to the open platform
-// Any body supplied will be encoded to XML
+// Any body supplied will be encoded to XML, except a *FileUpload which is sent as a multipart form.
+// The request is signed by Do so it can be signed again with a fresh timestamp if it has to be retried.
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
-	if !strings.HasPrefix(urlStr, "https") {
+	if !strings.HasPrefix(urlStr, "http") {
 		urlStr = fmt.Sprintf("rest%s", urlStr)
 	}
 
@@ -143,89 +358,84 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		return nil, errors.Wrap(err, "cant parse url")
 	}
 
-	var req *http.Request
+	if f, ok := body.(*FileUpload); ok {
+		return newMultipartRequest(method, u.String(), f)
+	}
 
 	// If we are sending a body url encode everything (even the xml for some reason)
 	if body != nil {
-		buf := new(bytes.Buffer)
-		buf.Write([]byte(xml.Header))
-
-		enc := xml.NewEncoder(buf)
-		err := enc.Encode(body)
+		payload, err := encodeXML(body)
 		if err != nil {
-			return nil, errors.Wrap(err, "cant encode body")
+			return nil, err
 		}
 
 		reqParams := url.Values{}
-		reqParams.Set("payload", buf.String())
-		reqParams.Set("sign_method", "sha256")
-		reqParams.Set("timestamp", fmt.Sprintf("%d", time.Now().Unix()*1000))
-		reqParams.Set("app_key", c.appKey)
+		reqParams.Set("payload", payload)
 
-		if c.accessToken != "" {
-			reqParams.Set("access_token", c.accessToken)
-		}
-		sig := c.Signature(strings.TrimPrefix(u.Path, "/rest"), reqParams, strings.NewReader(reqParams.Encode()))
-		reqParams.Set("sign", sig)
+		return newFormRequest(method, u.String(), reqParams)
+	}
 
-		req, err = http.NewRequest(method, u.String(), strings.NewReader(reqParams.Encode()))
-		if err != nil {
-			return nil, err
-		}
+	return http.NewRequest(method, u.String(), nil)
+}
+
+// encodeXML encodes v into an XML document with the XML header
+func encodeXML(v interface{}) (string, error) {
+	buf := new(bytes.Buffer)
+	buf.Write([]byte(xml.Header))
 
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
-		return req, nil
+	enc := xml.NewEncoder(buf)
+	if err := enc.Encode(v); err != nil {
+		return "", errors.Wrap(err, "cant encode body")
 	}
 
-	req, err = http.NewRequest(method, u.String(), nil)
+	return buf.String(), nil
+}
+
+// newFormRequest returns a request with params url encoded in the body
+func newFormRequest(method, urlStr string, params url.Values) (*http.Request, error) {
+	req, err := http.NewRequest(method, urlStr, strings.NewReader(params.Encode()))
 	if err != nil {
 		return nil, err
 	}
 
-	if body != nil {
-	}
-
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
 	return req, nil
 }
 
 // Do runs a http.Request adding in the various required query parameters if they weren't set by the body already.
 // It will marshal the data returned into the provided interface.
+//
+// The request is bound to ctx, if ctx is cancelled or its deadline passes the call is aborted
+// and ctx.Err() is returned unwrapped so it can be compared to context.Canceled or context.DeadlineExceeded.
+//
+// If the client has a RetryPolicy failed attempts are retried, each attempt is signed again with a fresh timestamp.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*LazadaResponse, error) {
-	var q url.Values
+	if ctx == nil {
+		return nil, errors.New("context must be non-nil")
+	}
 
-	if req.Body == nil {
-		q = req.URL.Query()
-		q.Set("sign_method", "sha256")
-		q.Set("timestamp", fmt.Sprintf("%d", time.Now().Unix()*1000))
-		q.Set("app_key", c.appKey)
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
 
-		if c.accessToken != "" {
-			q.Set("access_token", c.accessToken)
-		}
+	var resp *http.Response
+	var lazResp *LazadaResponse
+	var err error
 
-		var body io.ReadCloser
-		var err error
-		if req.Body != nil {
-			body, err = req.GetBody()
-			if err != nil {
-				return nil, err
-			}
+	api := apiName(c.apiPath(req.URL))
+	for attempt := 1; ; attempt++ {
+		resp, lazResp, err = c.roundTrip(ctx, req, attempt)
+		if err == nil || !c.retry.shouldRetry(req.Method, api, attempt, err) {
+			break
 		}
 
-		sig := c.Signature(strings.TrimPrefix(req.URL.Path, "/rest"), q, body)
-		q.Set("sign", sig)
-	}
-
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+		if waitErr := c.retry.wait(ctx, attempt); waitErr != nil {
+			return nil, waitErr
+		}
 	}
 
-	defer resp.Body.Close()
-
-	lazResp, err := CheckResponse(resp)
 	if err != nil {
 		return nil, err
 	}
@@ -247,6 +457,118 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Laz
 	return lazResp, err
 }
 
+// roundTrip signs and sends a single attempt of req through the middleware of the client.
+// The body of the returned response has already been read and can be read again.
+func (c *Client) roundTrip(ctx context.Context, req *http.Request, attempt int) (*http.Response, *LazadaResponse, error) {
+	api := apiName(c.apiPath(req.URL))
+
+	token, err := c.token(ctx, api)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.limiter != nil {
+		key := RateLimitKey{AppKey: c.appKey, AccessToken: token, API: api}
+		if err := c.limiter.Wait(ctx, key); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	r := req.Clone(ctx)
+	if err := c.sign(r, token); err != nil {
+		return nil, nil, err
+	}
+
+	return c.handler()(&Call{API: api, Attempt: attempt, Request: r, client: c, token: token})
+}
+
+// sign adds the system parameters and the signature to the request.
+// Requests with a form body are signed in the body, everything else in the query string.
+// Files in multipart bodies are not part of the signature.
+// Any query parameters of a request with a form body are moved into the body so they are signed with it.
+func (c *Client) sign(req *http.Request, token string) error {
+	api := c.apiPath(req.URL)
+
+	if !hasFormBody(req) {
+		q := req.URL.Query()
+		c.signParams(api, q, token)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(err, "cant read body")
+	}
+
+	params, err := url.ParseQuery(string(data))
+	if err != nil {
+		return errors.Wrap(err, "cant parse body")
+	}
+
+	for k, v := range req.URL.Query() {
+		params[k] = v
+	}
+	req.URL.RawQuery = ""
+
+	c.signParams(api, params, token)
+
+	encoded := params.Encode()
+	req.Body = ioutil.NopCloser(strings.NewReader(encoded))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(encoded)), nil
+	}
+	req.ContentLength = int64(len(encoded))
+
+	return nil
+}
+
+// hasFormBody reports if the request has a url encoded form body
+func hasFormBody(req *http.Request) bool {
+	return req.GetBody != nil && !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/")
+}
+
+// apiPath returns the path of the api a request is for, e.g. /brands/get for https://api.lazada.sg/rest/brands/get.
+// The base url and the auth url can have a path of their own, e.g. a proxy, so only rest following it is stripped.
+func (c *Client) apiPath(u *url.URL) string {
+	for _, base := range []*url.URL{c.BaseURL, c.AuthBaseURL} {
+		if base == nil || base.Host != u.Host {
+			continue
+		}
+
+		if prefix := base.Path + "rest"; strings.HasPrefix(u.Path, prefix+"/") {
+			return strings.TrimPrefix(u.Path, prefix)
+		}
+	}
+
+	// Absolute urls somewhere else, the api path follows the last rest
+	if i := strings.LastIndex(u.Path, "/rest/"); i >= 0 {
+		return u.Path[i+len("/rest"):]
+	}
+
+	return u.Path
+}
+
+// signParams sets the system parameters with a fresh timestamp and replaces any previous signature
+func (c *Client) signParams(api string, params url.Values, token string) {
+	params.Del("sign")
+	params.Set("sign_method", "sha256")
+	params.Set("timestamp", fmt.Sprintf("%d", time.Now().Unix()*1000))
+	params.Set("app_key", c.appKey)
+
+	if token != "" {
+		params.Set("access_token", token)
+	}
+
+	params.Set("sign", c.Signature(api, params, nil))
+}
+
 // CheckResponse makes sure we didn't receive an error from the platform and if we did it returns the error properly.
 func CheckResponse(r *http.Response) (*LazadaResponse, error) {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
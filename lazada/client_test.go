@@ -2,15 +2,51 @@ package lazada
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// setup starts a test HTTP server and returns a token client pointed at it.
+// Tests register handlers on mux for the API paths they call, prefixed with /rest.
+// Requests to absolute URLs such as the auth APIs are sent to the test server as well.
+func setup() (client *Client, mux *http.ServeMux, teardown func()) {
+	mux = http.NewServeMux()
+	server := httptest.NewServer(mux)
+	serverURL, _ := url.Parse(server.URL + "/")
+
+	hc := &http.Client{Transport: rewriteTransport{serverURL}}
+	client, err := NewClient("123456", "testsecretnotarealsecret", Singapore, WithHTTPClient(hc), WithBaseURL(server.URL))
+	if err != nil {
+		panic(err)
+	}
+	client = client.NewTokenClient("faketoken")
+
+	return client, mux, server.Close
+}
+
+// rewriteTransport sends every request to the test server
+type rewriteTransport struct {
+	url *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.url.Scheme
+	req.URL.Host = t.url.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
 func TestClient_Signature(t *testing.T) {
-	c := NewClient("123456", "testsecretnotarealsecret", Singapore)
+	c, err := NewClient("123456", "testsecretnotarealsecret", Singapore)
+	require.NoError(t, err)
 	req, err := http.NewRequest("GET",
 		"https://api.lazada.sg/rest/brands/get?offset=0&limit=100&app_key=123456&sign_method=sha256&timestamp=1537324254708", nil)
 
@@ -21,7 +57,8 @@ func TestClient_Signature(t *testing.T) {
 }
 
 func TestClient_SignatureWithPayload(t *testing.T) {
-	c := NewClient("123456", "testsecretnotarealsecret", Singapore)
+	c, err := NewClient("123456", "testsecretnotarealsecret", Singapore)
+	require.NoError(t, err)
 	c = c.NewTokenClient("faketoken")
 	req, err := http.NewRequest("POST",
 		"https://api.lazada.sg/rest/product/create?payload=%3C%3Fxml+version%3D%221.0%22+encoding%3D%22UTF-8%22%3F%3E%0A%3CRequest%3E%0A++++%3CProduct%3E%0A++++++++%3CAttributes%3E%0A++++++++++++%3Cname%3Etest+product+creation%3C%2Fname%3E%0A++++++++++++%3Cbrand%3EKid+Basix%3C%2Fbrand%3E%0A++++++++++++%3Cmaterial%3ECotton%3C%2Fmaterial%3E%0A++++++++++++%3Cwaterproof%3Ewaterproof%3C%2Fwaterproof%3E%0A++++++++++++%3Cwarranty_type%3ELocal+%28Singapore%29+manufacturer+warranty%3C%2Fwarranty_type%3E%0A++++++++++++%3Cwarranty%3E1+month%3C%2Fwarranty%3E%0A++++++++++++%3Cshort_description%3Etest+product+highlights%3C%2Fshort_description%3E%0A++++++++++++%3Cdescription%3Etest+product+description%3C%2Fdescription%3E%0A++++++++++++%3Cmodel%3Etest+model%3C%2Fmodel%3E%0A++++++++++++%3Crecommended_gender%3EMen%3C%2Frecommended_gender%3E%0A++++++++++++%3CHazmat%3EBattery%2C+Flammable%3C%2FHazmat%3E%0A++++++++%3C%2FAttributes%3E%0A++++++++%3CPrimaryCategory%3E10001958%3C%2FPrimaryCategory%3E%0A++++++++%3CSkus%3E%0A++++++++++++%3CSku%3E%0A++++++++++++++++%3CImages%3E%0A++++++++++++++++++++%3CImage%3Ehttps%3A%2F%2Fsg-live.slatic.net%2Foriginal%2Fb731a8098df7d606ab2e56efc650afcb.jpg%3C%2FImage%3E%0A++++++++++++++++%3C%2FImages%3E%0A++++++++++++++++%3CSellerSku%3Etest-product-creation-for-api%3C%2FSellerSku%3E%0A++++++++++++++++%3Cquantity%3E1%3C%2Fquantity%3E%0A++++++++++++++++%3Cpackage_length%3E1%3C%2Fpackage_length%3E%0A++++++++++++++++%3Cpackage_content%3Etest+whats+in+the+box%3C%2Fpackage_content%3E%0A++++++++++++++++%3Cpackage_width%3E1%3C%2Fpackage_width%3E%0A++++++++++++++++%3Cpackage_height%3E1%3C%2Fpackage_height%3E%0A++++++++++++++++%3Ccolor_family%3EBlack%3C%2Fcolor_family%3E%0A++++++++++++++++%3Cspecial_price%3E0.0%3C%2Fspecial_price%3E%0A++++++++++++++++%3Cprice%3E23.0%3C%2Fprice%3E%0A++++++++++++++++%3Cpackage_weight%3E1%3C%2Fpackage_weight%3E%0A++++++++++++%3C%2FSku%3E%0A++++++++%3C%2FSkus%3E%0A++++%3C%2FProduct%3E%0A%3C%2FRequest%3E&app_key=123456&sign_method=sha256&timestamp=1539870185083&access_token=faketoken", nil)
@@ -32,13 +69,109 @@ func TestClient_SignatureWithPayload(t *testing.T) {
 	assert.Equal(t, "4F912A7D7FF2B433CE5141291BA3A6B1DB2C069453927B271E6C67E414DAE1F4", sig)
 }
 
+func TestClient_DoTimeout(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	done := make(chan struct{})
+	defer close(done)
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.Products.Brands(ctx, nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	timeoutClient, err := NewClient("123456", "testsecretnotarealsecret", Singapore,
+		WithHTTPClient(&http.Client{}), WithTimeout(10*time.Millisecond), WithBaseURL(client.BaseURL.String()))
+	require.NoError(t, err)
+	_, err = timeoutClient.Products.Brands(context.Background(), nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestNewClient_Endpoints(t *testing.T) {
+	_, err := NewClient("123456", "testsecretnotarealsecret", Region("xx"))
+	assert.True(t, errors.Is(err, ErrUnknownRegion))
+
+	c, err := NewClient("123456", "testsecretnotarealsecret", Region("sandbox"),
+		WithEndpoints(map[Region]string{"sandbox": "https://api.lazada.test/rest"}))
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.lazada.test/", c.BaseURL.String())
+	assert.Equal(t, Region("sandbox"), c.Region())
+	assert.NoError(t, c.SetRegion(Malaysia))
+	assert.Equal(t, endpoints[Malaysia], c.BaseURL.String())
+
+	assert.True(t, errors.Is(c.SetRegion("xx"), ErrUnknownRegion))
+	assert.Equal(t, Region(Malaysia), c.Region())
+
+	c, err = NewClient("123456", "testsecretnotarealsecret", Region("xx"),
+		WithBaseURL("http://localhost:8080"), WithAuthURL("http://localhost:8081"))
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/", c.BaseURL.String())
+	assert.Equal(t, Region(""), c.Region())
+	assert.Equal(t, "http://localhost:8081/oauth/authorize?client_id=123456&redirect_uri=https%3A%2F%2Fexample.com&response_type=code&state=abc",
+		c.Auth.AuthURL("https://example.com", "abc"))
+
+	_, err = NewClient("123456", "testsecretnotarealsecret", Singapore, WithBaseURL("/relative"))
+	assert.Error(t, err)
+}
+
+func TestClient_BaseURLWithPath(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// A proxy that serves the apis below a path of its own, the base url can end with rest or not
+	// and its path can have rest in it
+	for base, path := range map[string]string{
+		"/proxy/rest":       "/proxy/rest/brands/get",
+		"/other/":           "/other/rest/brands/get",
+		"/rest/lazada/rest": "/rest/lazada/rest/brands/get",
+		"/api/rest/v2/":     "/api/rest/v2/rest/brands/get",
+	} {
+		client, err := NewClient("123456", "testsecretnotarealsecret", Singapore, WithBaseURL(server.URL+base))
+		require.NoError(t, err)
+
+		calls := 0
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			params := r.URL.Query()
+			sign := params.Get("sign")
+			params.Del("sign")
+			assert.Equal(t, client.Signature("/brands/get", params, nil), sign)
+			fmt.Fprint(w, `{"code":"0","data":[]}`)
+		})
+
+		var api string
+		client.Use(func(next CallHandler) CallHandler {
+			return func(call *Call) (*http.Response, *LazadaResponse, error) {
+				api = call.API
+				return next(call)
+			}
+		})
+
+		_, err = client.Products.Brands(context.Background(), nil)
+		assert.NoError(t, err, base)
+		assert.Equal(t, 1, calls, base)
+		assert.Equal(t, "GetBrands", api, base)
+	}
+}
+
 func TestSliceString(t *testing.T) {
 	out := SliceString([]string{"test"})
 	assert.Equal(t, `["test"]`, out)
 }
 
 func ExampleProductService_Create() {
-	client := NewClient("12345", "example", Singapore)
+	client, err := NewClient("12345", "example", Singapore)
+	if err != nil {
+		panic(err)
+	}
 
 	userClient := client.NewTokenClient("usertoken") // Set the a token obtained through oauth
 	userClient.SetRegion(Malaysia)                   // Change the region to Malaysia
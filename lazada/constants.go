@@ -1,18 +1,51 @@
 package lazada
 
+import (
+	"errors"
+)
+
 // API Names are all the paths to the various API calls that we use
 var apiNames = map[string]string{
-	"AccessToken":        "https://auth.lazada.com/rest/auth/token/create",
-	"RefreshToken":       "https://auth.lazada.com/rest/auth/token/refresh",
-	"GetBrands":          "/brands/get",
-	"CategoryTree":       "/category/tree/get",
-	"ImageMigrate":       "/image/migrate",
-	"CategoryAttributes": "/category/attributes/get",
-	"CreateProduct":      "/product/create",
-	"UpdateProduct":      "/product/update",
-	"GetProducts":        "/products/get",
+	"AccessToken":         "/auth/token/create",
+	"RefreshToken":        "/auth/token/refresh",
+	"GetBrands":           "/brands/get",
+	"QueryBrands":         "/category/brands/query",
+	"CategoryTree":        "/category/tree/get",
+	"SuggestCategory":     "/product/category/suggestion/get",
+	"ImageMigrate":        "/image/migrate",
+	"UploadImage":         "/image/upload",
+	"MigrateImages":       "/images/migrate",
+	"GetImageResponse":    "/image/response/get",
+	"CategoryAttributes":  "/category/attributes/get",
+	"CreateProduct":       "/product/create",
+	"UpdateProduct":       "/product/update",
+	"GetProducts":         "/products/get",
+	"UpdatePriceQuantity": "/product/price_quantity/update",
+	"RemoveProduct":       "/product/remove",
+	"DeactivateProduct":   "/product/deactivate",
+	"RemoveSKU":           "/product/sku/remove",
+	"GetItem":             "/product/item/get",
+	"GetQCStatus":         "/product/qc/status/get",
+
+	"GetOrders":             "/orders/get",
+	"GetOrder":              "/order/get",
+	"GetOrderItems":         "/order/items/get",
+	"GetMultipleOrderItems": "/orders/items/get",
+	"PackOrder":             "/order/pack",
+	"ReadyToShipOrder":      "/order/rts",
+	"CancelOrder":           "/order/cancel",
+	"GetFailureReasons":     "/order/failure_reason/get",
+	"GetDocument":           "/order/document/get",
+	"GetAWBHTML":            "/order/document/awb/html/get",
+	"GetAWBPDF":             "/order/document/awb/pdf/get",
 }
 
+// defaultAuthURL is where the token APIs and the OAuth authorize page are served
+const defaultAuthURL = "https://auth.lazada.com/"
+
+// ErrUnknownRegion is returned when a region has no endpoint
+var ErrUnknownRegion = errors.New("lazada: unknown region")
+
 type Region string
 
 const (
@@ -43,3 +76,16 @@ var endpoints = map[Region]string{
 	Myanmar:     "https://api.shop.com.mm/",
 	Malaysia:    "https://api.lazada.com.my/",
 }
+
+// apiName returns the name of the registered API with the path, e.g. GetBrands for /brands/get.
+// If the path is not a known API the path is returned.
+func apiName(path string) string {
+	apisMu.RLock()
+	defer apisMu.RUnlock()
+
+	if name, ok := apiPaths[path]; ok {
+		return name
+	}
+
+	return path
+}
@@ -10,6 +10,9 @@ type ProductRequest struct {
 type Attributes struct {
 	XMLName xml.Name `xml:"Attributes,omitempty"`
 	Attrs   StringMap
+
+	// Extra holds attributes that are not plain text, they are written after Attrs
+	Extra Elements `xml:",omitempty"`
 }
 
 type Images struct {
@@ -29,4 +32,7 @@ type Sku struct {
 	Images    *Images  `xml:"Images,omitempty"`
 	SellerSku string   `xml:"SellerSku"`
 	SkuAttrs  StringMap
+
+	// Extra holds attributes that are not plain text, they are written after SkuAttrs
+	Extra Elements `xml:",omitempty"`
 }
@@ -1,11 +1,74 @@
 package lazada
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 )
 
+// Sentinel errors for the documented platform error codes.
+// Use errors.Is to check an *ErrorResponse against them.
+var (
+	// The access token is invalid or expired
+	ErrInvalidToken = errors.New("lazada: invalid or expired access token")
+
+	// The refresh token is invalid or expired
+	ErrInvalidRefreshToken = errors.New("lazada: invalid or expired refresh token")
+
+	// The app or the API exceeded its call limit
+	ErrCallLimit = errors.New("lazada: call limit exceeded")
+
+	// A required parameter was not sent
+	ErrMissingParameter = errors.New("lazada: missing parameter")
+
+	// A parameter had an invalid value
+	ErrInvalidParameter = errors.New("lazada: invalid parameter")
+
+	// The signature did not match, usually caused by a wrong app secret
+	ErrInvalidSignature = errors.New("lazada: invalid signature")
+
+	// The app key is unknown or not allowed to call the API
+	ErrInvalidAppKey = errors.New("lazada: invalid app key")
+
+	// The seller sku does not exist
+	ErrSKUNotFound = errors.New("lazada: sku not found")
+
+	// The platform failed to process the call, it can be tried again later
+	ErrServiceUnavailable = errors.New("lazada: service unavailable")
+)
+
+var (
+	errorCodesMu sync.RWMutex
+
+	// errorCodes maps the codes returned by the platform to the sentinel errors
+	errorCodes = map[string]error{
+		"IllegalAccessToken":  ErrInvalidToken,
+		"IllegalRefreshToken": ErrInvalidRefreshToken,
+		"ApiCallLimit":        ErrCallLimit,
+		"AppCallLimit":        ErrCallLimit,
+		"MissingParameter":    ErrMissingParameter,
+		"InvalidParameter":    ErrInvalidParameter,
+		"IncompleteSignature": ErrInvalidSignature,
+		"InvalidApiKey":       ErrInvalidAppKey,
+		"MissingAppKey":       ErrInvalidAppKey,
+		"208":                 ErrSKUNotFound,
+		"ServiceTimeout":      ErrServiceUnavailable,
+		"ServiceUnavailable":  ErrServiceUnavailable,
+		"InternalError":       ErrServiceUnavailable,
+	}
+)
+
+// RegisterErrorCode makes errors.Is match an *ErrorResponse with code against target.
+// It can be used for codes that are not mapped by the library or to map to your own errors.
+func RegisterErrorCode(code string, target error) {
+	errorCodesMu.Lock()
+	defer errorCodesMu.Unlock()
+
+	errorCodes[code] = target
+}
+
 // Error response is used to return as much data as possible to the calling application to help with dealing with any API issues.
 type ErrorResponse struct {
 	Response *http.Response
@@ -17,25 +80,66 @@ type ErrorResponse struct {
 	Detail    []*ErrorDetails `json:"detail,omitempty"`
 }
 
+// ErrorDetails is a field level error, mostly returned when a product fails validation
 type ErrorDetails struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
+	Field     string `json:"field"`
+	Message   string `json:"message"`
+	SellerSKU string `json:"seller_sku"`
 }
 
 func (r *ErrorResponse) Error() string {
+	var sb strings.Builder
 
-	if len(r.Detail) > 0 {
-		var sb strings.Builder
-		sb.WriteString(fmt.Sprintf("%v %v: %v %v %v \n",
-			r.Response.Request.Method, r.Response.Request.URL, r.Code, r.Type, r.Message))
+	if r.Response != nil && r.Response.Request != nil {
+		sb.WriteString(fmt.Sprintf("%v %v: ", r.Response.Request.Method, r.Response.Request.URL))
+	}
+	sb.WriteString(fmt.Sprintf("%v %v %v", r.Code, r.Type, r.Message))
 
+	if len(r.Detail) > 0 {
+		sb.WriteString(" \n")
 		for _, v := range r.Detail {
 			sb.WriteString(fmt.Sprintf("Field: %s, Message: %s \n", v.Field, v.Message))
 		}
+	}
+
+	return sb.String()
+}
+
+// Is reports if the error code matches one of the sentinel errors, so errors.Is(err, ErrInvalidToken) works.
+// Server errors without a code match ErrServiceUnavailable.
+func (r *ErrorResponse) Is(target error) bool {
+	errorCodesMu.RLock()
+	mapped, ok := errorCodes[r.Code]
+	errorCodesMu.RUnlock()
+
+	if ok {
+		return mapped == target
+	}
+
+	if r.Code == "" && r.Response != nil && r.Response.StatusCode >= 500 {
+		return target == ErrServiceUnavailable
+	}
+
+	return false
+}
+
+// ByField groups the error details by the field they are about
+func (r *ErrorResponse) ByField() map[string][]*ErrorDetails {
+	out := make(map[string][]*ErrorDetails)
+	for _, d := range r.Detail {
+		out[d.Field] = append(out[d.Field], d)
+	}
+
+	return out
+}
 
-		return sb.String()
+// BySKU groups the error details by the seller sku they are about.
+// Details that are not about a single sku are grouped under an empty string.
+func (r *ErrorResponse) BySKU() map[string][]*ErrorDetails {
+	out := make(map[string][]*ErrorDetails)
+	for _, d := range r.Detail {
+		out[d.SellerSKU] = append(out[d.SellerSKU], d)
 	}
 
-	return fmt.Sprintf("%v %v: %v %v %v",
-		r.Response.Request.Method, r.Response.Request.URL, r.Code, r.Type, r.Message)
+	return out
 }
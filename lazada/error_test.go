@@ -0,0 +1,47 @@
+package lazada
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponse_Is(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":"IllegalAccessToken","type":"ISV","message":"token expired"}`)
+	})
+
+	_, err := client.Products.Brands(context.Background(), nil)
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+	assert.False(t, errors.Is(err, ErrCallLimit))
+
+	var errResp *ErrorResponse
+	require.True(t, errors.As(err, &errResp))
+	assert.Equal(t, "IllegalAccessToken", errResp.Code)
+}
+
+func TestErrorResponse_NoResponse(t *testing.T) {
+	err := &ErrorResponse{Code: "InternalError", Type: "ISP", Message: "oops"}
+
+	assert.Equal(t, "InternalError ISP oops", err.Error())
+	assert.True(t, errors.Is(err, ErrServiceUnavailable))
+}
+
+func TestErrorResponse_BySKU(t *testing.T) {
+	err := &ErrorResponse{Code: "201", Detail: []*ErrorDetails{
+		{Field: "price", Message: "invalid", SellerSKU: "a"},
+		{Field: "quantity", Message: "invalid", SellerSKU: "a"},
+		{Field: "price", Message: "invalid", SellerSKU: "b"},
+	}}
+
+	assert.Len(t, err.BySKU()["a"], 2)
+	assert.Len(t, err.ByField()["price"], 2)
+}
@@ -0,0 +1,230 @@
+package lazada
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// IDList is a list of ids that is encoded into a query parameter the way the open platform expects, e.g. [1,2,3]
+type IDList []int64
+
+// EncodeValues implements query.Encoder
+func (l IDList) EncodeValues(key string, v *url.Values) error {
+	v.Set(key, sliceInt64(l))
+	return nil
+}
+
+// DeliveryDropship is the delivery type used for orders shipped by the seller
+const DeliveryDropship = "dropship"
+
+// PackOptions are used to set order items to packed
+type PackOptions struct {
+	// The order items to pack
+	OrderItemIDs IDList `url:"order_item_ids"`
+
+	// The shipment provider that will deliver the items
+	ShippingProvider string `url:"shipping_provider"`
+
+	// Defaults to DeliveryDropship
+	DeliveryType string `url:"delivery_type"`
+}
+
+// ReadyToShipOptions are used to mark packed order items as ready to ship
+type ReadyToShipOptions struct {
+	// The order items that are ready to ship
+	OrderItemIDs IDList `url:"order_item_ids"`
+
+	// The shipment provider that was used when packing
+	ShipmentProvider string `url:"shipment_provider"`
+
+	// The tracking number returned when packing
+	TrackingNumber string `url:"tracking_number"`
+
+	// Defaults to DeliveryDropship
+	DeliveryType string `url:"delivery_type"`
+}
+
+type cancelOptions struct {
+	OrderItemID  int64  `url:"order_item_id"`
+	ReasonID     int    `url:"reason_id"`
+	ReasonDetail string `url:"reason_detail,omitempty"`
+}
+
+type documentOptions struct {
+	DocType      DocumentType `url:"doc_type,omitempty"`
+	OrderItemIDs IDList       `url:"order_item_ids"`
+}
+
+// FulfilledOrderItem is returned for every item that was packed or marked ready to ship
+type FulfilledOrderItem struct {
+	OrderItemID         int64  `json:"order_item_id"`
+	PurchaseOrderID     string `json:"purchase_order_id"`
+	PurchaseOrderNumber string `json:"purchase_order_number"`
+	PackageID           string `json:"package_id"`
+	ShipmentProvider    string `json:"shipment_provider"`
+	TrackingNumber      string `json:"tracking_number"`
+}
+
+type fulfilmentResponse struct {
+	OrderItems []*FulfilledOrderItem `json:"order_items"`
+}
+
+// Pack sets the order items to packed with the given shipment provider
+// Requires a client access token
+func (o *OrderService) Pack(ctx context.Context, opts *PackOptions) ([]*FulfilledOrderItem, error) {
+	// Copy so the default delivery type is not written back to the options of the caller
+	pack := PackOptions{}
+	if opts != nil {
+		pack = *opts
+	}
+
+	if pack.DeliveryType == "" {
+		pack.DeliveryType = DeliveryDropship
+	}
+
+	resp := &fulfilmentResponse{}
+	_, err := o.client.CallAPI(ctx, "PackOrder", &pack, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.OrderItems, nil
+}
+
+// ReadyToShip marks the packed order items as ready to ship
+// Requires a client access token
+func (o *OrderService) ReadyToShip(ctx context.Context, opts *ReadyToShipOptions) ([]*FulfilledOrderItem, error) {
+	// Copy so the default delivery type is not written back to the options of the caller
+	rts := ReadyToShipOptions{}
+	if opts != nil {
+		rts = *opts
+	}
+
+	if rts.DeliveryType == "" {
+		rts.DeliveryType = DeliveryDropship
+	}
+
+	resp := &fulfilmentResponse{}
+	_, err := o.client.CallAPI(ctx, "ReadyToShipOrder", &rts, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.OrderItems, nil
+}
+
+// Cancel cancels a single order item
+// The reason id must be one of the cancel reasons returned by CancelReasons
+// Requires a client access token
+func (o *OrderService) Cancel(ctx context.Context, orderItemID int64, reasonID int, detail string) error {
+	opts := &cancelOptions{OrderItemID: orderItemID, ReasonID: reasonID, ReasonDetail: detail}
+	_, err := o.client.CallAPI(ctx, "CancelOrder", opts, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reason is a failure or cancellation reason that can be given when changing an order status
+type Reason struct {
+	ReasonID int    `json:"reason_id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+}
+
+// FailureReasons returns all the failure and cancellation reasons
+// Requires a client access token
+func (o *OrderService) FailureReasons(ctx context.Context) ([]*Reason, error) {
+	reasons := []*Reason{}
+	_, err := o.client.CallAPI(ctx, "GetFailureReasons", nil, nil, &reasons)
+	if err != nil {
+		return nil, err
+	}
+
+	return reasons, nil
+}
+
+// CancelReasons returns only the reasons that can be used with Cancel
+// Requires a client access token
+func (o *OrderService) CancelReasons(ctx context.Context) ([]*Reason, error) {
+	reasons, err := o.FailureReasons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cancel := []*Reason{}
+	for _, r := range reasons {
+		if r.Type == "canceled" {
+			cancel = append(cancel, r)
+		}
+	}
+
+	return cancel, nil
+}
+
+// DocumentType is the kind of document returned by Document
+type DocumentType string
+
+const (
+	DocumentInvoice         DocumentType = "invoice"
+	DocumentShippingLabel   DocumentType = "shippingLabel"
+	DocumentCarrierManifest DocumentType = "carrierManifest"
+)
+
+// AWBFormat is the format of the airway bill returned by AWB
+type AWBFormat string
+
+const (
+	AWBHTML AWBFormat = "html"
+	AWBPDF  AWBFormat = "pdf"
+)
+
+// Document is a printable order document
+// File holds the decoded HTML or PDF content
+type Document struct {
+	DocumentType string `json:"document_type"`
+	MimeType     string `json:"mime_type"`
+	File         []byte `json:"file"`
+}
+
+// Document returns the requested document for the order items
+// Requires a client access token
+func (o *OrderService) Document(ctx context.Context, docType DocumentType, orderItemIDs []int64) (*Document, error) {
+	return o.document(ctx, "GetDocument", &documentOptions{DocType: docType, OrderItemIDs: orderItemIDs})
+}
+
+// AWB returns the airway bill for the order items in either HTML or PDF format
+// Requires a client access token
+func (o *OrderService) AWB(ctx context.Context, format AWBFormat, orderItemIDs []int64) (*Document, error) {
+	var api string
+	switch format {
+	case AWBHTML:
+		api = "GetAWBHTML"
+	case AWBPDF:
+		api = "GetAWBPDF"
+	default:
+		return nil, fmt.Errorf("unknown airway bill format %q", format)
+	}
+
+	return o.document(ctx, api, &documentOptions{OrderItemIDs: orderItemIDs})
+}
+
+func (o *OrderService) document(ctx context.Context, api string, opts *documentOptions) (*Document, error) {
+	// The file is base64 encoded which the json decoder takes care of for []byte
+	resp := struct {
+		Document *Document `json:"document"`
+	}{}
+	_, err := o.client.CallAPI(ctx, api, opts, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Document == nil {
+		return nil, errors.New("no document returned")
+	}
+
+	return resp.Document, nil
+}
@@ -0,0 +1,137 @@
+package lazada
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FileUpload is a body for NewRequest that is sent as a multipart form holding a single file
+type FileUpload struct {
+	// The name of the form field, e.g. "image"
+	Field string
+
+	Filename string
+	Content  io.Reader
+}
+
+// newMultipartRequest returns a request with the file in a multipart body.
+// The file is read into memory so the request can be sent again if it is retried.
+func newMultipartRequest(method, urlStr string, f *FileUpload) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+
+	part, err := w.CreateFormFile(f.Field, f.Filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "cant create multipart body")
+	}
+
+	if _, err := io.Copy(part, f.Content); err != nil {
+		return nil, errors.Wrap(err, "cant read file")
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "cant create multipart body")
+	}
+
+	req, err := http.NewRequest(method, urlStr, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req, nil
+}
+
+// UploadImage uploads an image file into the Lazada platform, use it for images that are not publicly reachable
+// Requires a client access token
+func (p *ProductService) UploadImage(ctx context.Context, r io.Reader, filename string) (*ImageResponse, error) {
+	img := &ImageResponse{}
+	_, err := p.client.CallAPI(ctx, "UploadImage", nil, &FileUpload{Field: "image", Filename: filename, Content: r}, img)
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// MaxMigrateImages is the most images MigrateImages can move in one batch
+const MaxMigrateImages = 8
+
+// ImageBatchPollInterval is how long MigrateImages waits between checks of the batch result
+var ImageBatchPollInterval = 2 * time.Second
+
+type migrateImagesRequest struct {
+	XMLName xml.Name `xml:"Request"`
+	URLs    []string `xml:"Images>Url"`
+}
+
+// MigratedImage is an image moved into the Lazada platform
+type MigratedImage struct {
+	URL      string `json:"url"`
+	HashCode string `json:"hash_code"`
+}
+
+// ImageBatchResult is the outcome of MigrateImages
+type ImageBatchResult struct {
+	// The images that were migrated
+	Images []*MigratedImage `json:"images"`
+
+	// The images that failed, the field holds the url of the image
+	Errors []*ErrorDetails `json:"errors"`
+}
+
+// MigrateImages moves publicly accessible images into the Lazada platform in one batch.
+// The batch is processed asynchronously, MigrateImages waits until it is done checking every ImageBatchPollInterval
+// or until ctx is done. A batch the platform never finishes is polled until then so ctx should have a deadline,
+// WithTimeout only limits each call.
+// Requires a client access token
+func (p *ProductService) MigrateImages(ctx context.Context, urls []string) (*ImageBatchResult, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no images to migrate")
+	}
+
+	if len(urls) > MaxMigrateImages {
+		return nil, fmt.Errorf("at most %d images can be migrated at once", MaxMigrateImages)
+	}
+
+	batch := struct {
+		BatchID string `json:"batch_id"`
+	}{}
+	_, err := p.client.CallAPI(ctx, "MigrateImages", nil, &migrateImagesRequest{URLs: urls}, &batch)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.BatchID == "" {
+		return nil, errors.New("no batch id returned")
+	}
+
+	params := url.Values{"batch_id": {batch.BatchID}}
+	for {
+		result := &ImageBatchResult{}
+		_, err := p.client.CallAPI(ctx, "GetImageResponse", params, nil, result)
+		if err != nil {
+			return nil, err
+		}
+
+		// Until the batch is done there are neither images nor errors
+		if len(result.Images)+len(result.Errors) > 0 {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(ImageBatchPollInterval):
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductService_UploadImage(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.retry = &testRetryPolicy
+
+	calls := 0
+	mux.HandleFunc("/rest/image/upload", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "POST", r.Method)
+
+		f, header, err := r.FormFile("image")
+		require.NoError(t, err)
+		data, _ := ioutil.ReadAll(f)
+		assert.Equal(t, "fakejpeg", string(data))
+		assert.Equal(t, "photo.jpg", header.Filename)
+
+		// The system parameters are signed in the query string without the file
+		q := r.URL.Query()
+		sign := q.Get("sign")
+		q.Del("sign")
+		assert.Equal(t, client.Signature("/image/upload", q, nil), sign)
+
+		if calls == 1 {
+			fmt.Fprint(w, `{"code":"ApiCallLimit","type":"ISP","message":"slow down"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"code":"0","data":{"image":{"hash_code":"abc","url":"https://sg-live.slatic.net/original/abc.jpg"}}}`)
+	})
+
+	img, err := client.Products.UploadImage(context.Background(), strings.NewReader("fakejpeg"), "photo.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "abc", img.Image.HashCode)
+}
+
+func TestProductService_MigrateImages(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	old := ImageBatchPollInterval
+	ImageBatchPollInterval = time.Millisecond
+	defer func() { ImageBatchPollInterval = old }()
+
+	mux.HandleFunc("/rest/images/migrate", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Contains(t, r.PostForm.Get("payload"), "<Images><Url>https://a.jpg</Url><Url>https://b.jpg</Url></Images>")
+		fmt.Fprint(w, `{"code":"0","data":{"batch_id":"batch-1"}}`)
+	})
+
+	polls := 0
+	mux.HandleFunc("/rest/image/response/get", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		assert.Equal(t, "batch-1", r.URL.Query().Get("batch_id"))
+		if polls < 3 {
+			fmt.Fprint(w, `{"code":"0","data":{}}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"code":"0","data":{"images":[{"url":"https://lazada/a.jpg","hash_code":"a"}],
+			"errors":[{"field":"https://b.jpg","message":"cant download"}]}}`)
+	})
+
+	result, err := client.Products.MigrateImages(context.Background(), []string{"https://a.jpg", "https://b.jpg"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, polls)
+	require.Len(t, result.Images, 1)
+	assert.Equal(t, "a", result.Images[0].HashCode)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "https://b.jpg", result.Errors[0].Field)
+
+	_, err = client.Products.MigrateImages(context.Background(), nil)
+	assert.EqualError(t, err, "no images to migrate")
+
+	_, err = client.Products.MigrateImages(context.Background(), make([]string, MaxMigrateImages+1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,260 @@
+package lazada
+
+import "context"
+
+// fetchFunc retrieves the page starting at offset.
+// more reports if there could be another page after this one.
+type fetchFunc func(ctx context.Context, offset int) (items []interface{}, more bool, err error)
+
+type page struct {
+	items []interface{}
+	more  bool
+	err   error
+}
+
+// pager lazily walks through the pages returned by fetch
+type pager struct {
+	ctx      context.Context
+	fetch    fetchFunc
+	offset   int
+	prefetch bool
+
+	items   []interface{}
+	idx     int
+	cur     interface{}
+	more    bool
+	err     error
+	pending chan page
+}
+
+func newPager(ctx context.Context, offset int, fetch fetchFunc) *pager {
+	return &pager{ctx: ctx, fetch: fetch, offset: offset, more: true}
+}
+
+func (p *pager) next() bool {
+	for p.idx >= len(p.items) {
+		if p.err != nil || !p.more {
+			return false
+		}
+
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+
+		pg := p.load()
+		if pg.err != nil {
+			p.err = pg.err
+			return false
+		}
+
+		p.items, p.idx, p.more = pg.items, 0, pg.more && len(pg.items) > 0
+		// The api can return fewer items than asked for, e.g. when it caps the page size
+		p.offset += len(pg.items)
+
+		if p.more && p.prefetch {
+			p.startPrefetch()
+		}
+	}
+
+	p.cur = p.items[p.idx]
+	p.idx++
+	return true
+}
+
+// load returns the page at the current offset, waiting for the prefetched page if there is one
+func (p *pager) load() page {
+	if p.pending == nil {
+		items, more, err := p.fetch(p.ctx, p.offset)
+		return page{items: items, more: more, err: err}
+	}
+
+	pending := p.pending
+	p.pending = nil
+
+	select {
+	case pg := <-pending:
+		return pg
+	case <-p.ctx.Done():
+		return page{err: p.ctx.Err()}
+	}
+}
+
+func (p *pager) startPrefetch() {
+	// Buffered so the goroutine can always finish even if the iterator is abandoned
+	p.pending = make(chan page, 1)
+
+	go func(ctx context.Context, offset int, out chan<- page) {
+		items, more, err := p.fetch(ctx, offset)
+		out <- page{items: items, more: more, err: err}
+	}(p.ctx, p.offset, p.pending)
+}
+
+// BrandIterator pages through all the brands in a region
+type BrandIterator struct {
+	p *pager
+}
+
+// IterBrands returns an iterator over all the brands starting from opts.Offset
+// opts.Limit is used as the page size, if opts is nil or has no limit then the default options are used
+func (p *ProductService) IterBrands(ctx context.Context, opts *ListOptions) *BrandIterator {
+	o := DefaultListOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Limit <= 0 {
+		o.Limit = DefaultListOptions.Limit
+	}
+
+	return &BrandIterator{p: newPager(ctx, o.Offset, func(ctx context.Context, offset int) ([]interface{}, bool, error) {
+		brands, err := p.Brands(ctx, &ListOptions{Offset: offset, Limit: o.Limit})
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(brands))
+		for i, b := range brands {
+			items[i] = b
+		}
+
+		// The brands api has no total, only an empty page shows the end
+		return items, len(brands) > 0, nil
+	})}
+}
+
+// Prefetch makes the iterator fetch the next page in the background while the current one is being read
+func (it *BrandIterator) Prefetch() *BrandIterator {
+	it.p.prefetch = true
+	return it
+}
+
+// Next advances to the next brand, it returns false when there are no more brands or an error occurred
+func (it *BrandIterator) Next() bool {
+	return it.p.next()
+}
+
+// Brand returns the current brand
+func (it *BrandIterator) Brand() *Brand {
+	b, _ := it.p.cur.(*Brand)
+	return b
+}
+
+// Err returns the error that stopped the iteration if any
+func (it *BrandIterator) Err() error {
+	return it.p.err
+}
+
+// ProductIterator pages through all the products matching the search options
+type ProductIterator struct {
+	p *pager
+}
+
+// IterProducts returns an iterator over all the products matching opts
+// opts.Limit is used as the page size, if opts is nil or has no limit then the default options are used
+func (p *ProductService) IterProducts(ctx context.Context, opts *SearchOptions) *ProductIterator {
+	o := SearchOptions{Offset: DefaultListOptions.Offset, Limit: DefaultListOptions.Limit}
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Limit <= 0 {
+		o.Limit = DefaultListOptions.Limit
+	}
+
+	return &ProductIterator{p: newPager(ctx, o.Offset, func(ctx context.Context, offset int) ([]interface{}, bool, error) {
+		search := o
+		search.Offset = offset
+
+		resp, err := p.Get(ctx, &search)
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(resp.Products))
+		for i, prod := range resp.Products {
+			items[i] = prod
+		}
+
+		return items, offset+len(resp.Products) < resp.TotalProducts, nil
+	})}
+}
+
+// Prefetch makes the iterator fetch the next page in the background while the current one is being read
+func (it *ProductIterator) Prefetch() *ProductIterator {
+	it.p.prefetch = true
+	return it
+}
+
+// Next advances to the next product, it returns false when there are no more products or an error occurred
+func (it *ProductIterator) Next() bool {
+	return it.p.next()
+}
+
+// Product returns the current product
+func (it *ProductIterator) Product() *GetProduct {
+	prod, _ := it.p.cur.(*GetProduct)
+	return prod
+}
+
+// Err returns the error that stopped the iteration if any
+func (it *ProductIterator) Err() error {
+	return it.p.err
+}
+
+// OrderIterator pages through all the orders matching the search options
+type OrderIterator struct {
+	p *pager
+}
+
+// IterOrders returns an iterator over all the orders matching opts
+// opts.Limit is used as the page size, if opts is nil or has no limit then the default options are used
+func (o *OrderService) IterOrders(ctx context.Context, opts *OrderSearchOptions) *OrderIterator {
+	search := OrderSearchOptions{Offset: DefaultListOptions.Offset, Limit: DefaultListOptions.Limit}
+	if opts != nil {
+		search = *opts
+	}
+
+	if search.Limit <= 0 {
+		search.Limit = DefaultListOptions.Limit
+	}
+
+	return &OrderIterator{p: newPager(ctx, search.Offset, func(ctx context.Context, offset int) ([]interface{}, bool, error) {
+		s := search
+		s.Offset = offset
+
+		resp, err := o.GetOrders(ctx, &s)
+		if err != nil {
+			return nil, false, err
+		}
+
+		items := make([]interface{}, len(resp.Orders))
+		for i, order := range resp.Orders {
+			items[i] = order
+		}
+
+		return items, offset+len(resp.Orders) < resp.CountTotal, nil
+	})}
+}
+
+// Prefetch makes the iterator fetch the next page in the background while the current one is being read
+func (it *OrderIterator) Prefetch() *OrderIterator {
+	it.p.prefetch = true
+	return it
+}
+
+// Next advances to the next order, it returns false when there are no more orders or an error occurred
+func (it *OrderIterator) Next() bool {
+	return it.p.next()
+}
+
+// Order returns the current order
+func (it *OrderIterator) Order() *Order {
+	order, _ := it.p.cur.(*Order)
+	return order
+}
+
+// Err returns the error that stopped the iteration if any
+func (it *OrderIterator) Err() error {
+	return it.p.err
+}
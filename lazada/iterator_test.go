@@ -0,0 +1,109 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductService_IterBrands(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		switch offset {
+		case 0:
+			fmt.Fprint(w, `{"code":"0","data":[{"brand_id":1},{"brand_id":2}]}`)
+		case 2:
+			fmt.Fprint(w, `{"code":"0","data":[{"brand_id":3}]}`)
+		case 3:
+			fmt.Fprint(w, `{"code":"0","data":[]}`)
+		default:
+			t.Errorf("unexpected offset %d", offset)
+		}
+	})
+
+	for _, prefetch := range []bool{false, true} {
+		calls = 0
+		it := client.Products.IterBrands(context.Background(), &ListOptions{Limit: 2})
+		if prefetch {
+			it.Prefetch()
+		}
+
+		ids := []int{}
+		for it.Next() {
+			ids = append(ids, it.Brand().BrandID)
+		}
+
+		require.NoError(t, it.Err())
+		assert.Equal(t, []int{1, 2, 3}, ids)
+		assert.Equal(t, 3, calls)
+	}
+}
+
+func TestProductService_IterBrands_CappedPages(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	// The server returns at most 2 brands whatever limit is asked for
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		brands := []string{}
+		for i := offset; i < offset+2 && i < 5; i++ {
+			brands = append(brands, fmt.Sprintf(`{"brand_id":%d}`, i))
+		}
+		fmt.Fprintf(w, `{"code":"0","data":[%s]}`, strings.Join(brands, ","))
+	})
+
+	it := client.Products.IterBrands(context.Background(), &ListOptions{Limit: 10})
+	ids := []int{}
+	for it.Next() {
+		ids = append(ids, it.Brand().BrandID)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, ids)
+}
+
+func TestProductService_IterProducts(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/products/get", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		fmt.Fprintf(w, `{"code":"0","data":{"total_products":3,"products":[{"item_id":%d}]}}`, offset+1)
+	})
+
+	it := client.Products.IterProducts(context.Background(), &SearchOptions{Limit: 1})
+
+	ids := []int{}
+	for it.Next() {
+		ids = append(ids, it.Product().ItemID)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []int{1, 2, 3}, ids)
+}
+
+func TestOrderService_IterOrdersCancelled(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	it := client.Orders.IterOrders(ctx, nil)
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+}
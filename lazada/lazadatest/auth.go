@@ -0,0 +1,79 @@
+package lazadatest
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/Teddy-Schmitz/go-lazada/lazada"
+)
+
+// tokenResponse is the body of the token apis, it only has the fields the platform returns
+// so the client sets when the token was retrieved itself
+type tokenResponse struct {
+	Code      string `json:"code"`
+	RequestID string `json:"request_id"`
+
+	AccountID        string `json:"account_id"`
+	Account          string `json:"account"`
+	Country          string `json:"country"`
+	AccountPlatform  string `json:"account_platform"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+}
+
+func newTokenResponse(t *lazada.Token, requestID string) *tokenResponse {
+	return &tokenResponse{
+		Code:             "0",
+		RequestID:        requestID,
+		AccountID:        t.AccountID,
+		Account:          t.Account,
+		Country:          t.Country,
+		AccountPlatform:  t.AccountPlatform,
+		AccessToken:      t.AccessToken,
+		RefreshToken:     t.RefreshToken,
+		ExpiresIn:        t.ExpiresIn,
+		RefreshExpiresIn: t.RefreshExpiresIn,
+	}
+}
+
+// newToken issues a new token and makes the server accept it
+func (s *Server) newToken() *lazada.Token {
+	id := s.newRequestID()
+	t := &lazada.Token{
+		AccountID:        "100",
+		Account:          "seller@example.com",
+		Country:          "sg",
+		AccessToken:      "access-" + id,
+		RefreshToken:     "refresh-" + id,
+		ExpiresIn:        604800,
+		RefreshExpiresIn: 2592000,
+	}
+
+	if len(s.tokens) > 0 {
+		s.tokens[t.AccessToken] = true
+	}
+	s.refresh[t.RefreshToken] = true
+
+	return t
+}
+
+func (s *Server) createToken(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	if params.Get("code") == "" {
+		return nil, &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "code is required"}
+	}
+
+	return s.newToken(), nil
+}
+
+func (s *Server) refreshToken(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	old := params.Get("refresh_token")
+	if !s.refresh[old] {
+		return nil, &lazada.ErrorResponse{Code: "IllegalRefreshToken", Type: "ISV",
+			Message: fmt.Sprintf("the refresh token %s is invalid or expired", old)}
+	}
+
+	delete(s.refresh, old)
+	return s.newToken(), nil
+}
@@ -0,0 +1,120 @@
+package lazadatest
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/Teddy-Schmitz/go-lazada/lazada"
+)
+
+// removeSKUs deletes the skus remove returns true for and drops products left without skus
+func (s *Server) removeSKUs(remove func(p *lazada.GetProduct, sku *lazada.ProductSKU) bool) {
+	products := s.products[:0]
+	for _, p := range s.products {
+		skus := p.SKUs[:0]
+		for _, sku := range p.SKUs {
+			if !remove(p, sku) {
+				skus = append(skus, sku)
+			}
+		}
+		p.SKUs = skus
+
+		if len(p.SKUs) > 0 {
+			products = append(products, p)
+		}
+	}
+
+	s.products = products
+}
+
+func (s *Server) removeProduct(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	list := map[string]bool{}
+	for _, sku := range parseList(params.Get("seller_sku_list")) {
+		list[sku] = true
+	}
+
+	if len(list) == 0 {
+		return nil, &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "seller_sku_list is required"}
+	}
+
+	s.removeSKUs(func(p *lazada.GetProduct, sku *lazada.ProductSKU) bool { return list[sku.SellerSKU] })
+	return nil, nil
+}
+
+func (s *Server) removeSKU(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	list := map[string]bool{}
+	for _, id := range parseList(params.Get("seller_sku_list")) {
+		list[id] = true
+	}
+
+	s.removeSKUs(func(p *lazada.GetProduct, sku *lazada.ProductSKU) bool {
+		return list[fmt.Sprintf("SkuId_%d_%d", p.ItemID, sku.SkuID)]
+	})
+	return nil, nil
+}
+
+func (s *Server) deactivateProduct(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	req, errResp := parsePayload(params)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	id, _ := strconv.Atoi(req.text("Product", "ItemId"))
+	p := s.findItem(id)
+	if p == nil {
+		return nil, invalidParameter("item %d not found", id)
+	}
+
+	only := map[string]bool{}
+	for _, n := range req.all("Product", "Skus", "SellerSku") {
+		only[n.Content] = true
+	}
+
+	for _, sku := range p.SKUs {
+		if len(only) == 0 || only[sku.SellerSKU] {
+			sku.Status = "inactive"
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Server) findItem(id int) *lazada.GetProduct {
+	for _, p := range s.products {
+		if p.ItemID == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) getItem(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	if sku := params.Get("seller_sku"); sku != "" {
+		p, _ := s.findSKU(sku)
+		if p == nil {
+			return nil, &lazada.ErrorResponse{Code: "208", Type: "ISV", Message: "SellerSku not found"}
+		}
+		return p, nil
+	}
+
+	id, _ := strconv.Atoi(params.Get("item_id"))
+	p := s.findItem(id)
+	if p == nil {
+		return nil, invalidParameter("item %d not found", id)
+	}
+
+	return p, nil
+}
+
+func (s *Server) getQCStatus(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	statuses := []*lazada.QCStatus{}
+	for _, sellerSKU := range parseList(params.Get("seller_skus")) {
+		if _, sku := s.findSKU(sellerSKU); sku != nil {
+			statuses = append(statuses, &lazada.QCStatus{SellerSKU: sellerSKU, Status: "approved"})
+		}
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,211 @@
+package lazadatest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/Teddy-Schmitz/go-lazada/lazada"
+)
+
+// AddOrder adds an order and its items, the order id of the items is set to the id of the order
+func (s *Server) AddOrder(order *lazada.Order, items ...*lazada.OrderItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range items {
+		item.OrderID = order.OrderID
+		if item.Status == "" {
+			item.Status = "pending"
+		}
+	}
+
+	order.ItemsCount = len(items)
+	s.orders = append(s.orders, order)
+	s.orderItems[order.OrderID] = append(s.orderItems[order.OrderID], items...)
+	s.updateStatuses(order.OrderID)
+}
+
+// OrderItem returns the order item with the id or nil
+func (s *Server) OrderItem(id int64) *lazada.OrderItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.findOrderItem(id)
+}
+
+func (s *Server) findOrderItem(id int64) *lazada.OrderItem {
+	for _, items := range s.orderItems {
+		for _, item := range items {
+			if item.OrderItemID == id {
+				return item
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateStatuses sets the statuses of the order to the distinct statuses of its items
+func (s *Server) updateStatuses(orderID int64) {
+	for _, order := range s.orders {
+		if order.OrderID != orderID {
+			continue
+		}
+
+		seen := map[string]bool{}
+		order.Statuses = []string{}
+		for _, item := range s.orderItems[orderID] {
+			if !seen[item.Status] {
+				seen[item.Status] = true
+				order.Statuses = append(order.Statuses, item.Status)
+			}
+		}
+	}
+}
+
+func (s *Server) getOrders(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	if params.Get("created_after") == "" && params.Get("update_after") == "" {
+		return nil, &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "created_after or update_after is required"}
+	}
+
+	status := params.Get("status")
+
+	matched := []*lazada.Order{}
+	for _, order := range s.orders {
+		if status != "" && !contains(order.Statuses, status) {
+			continue
+		}
+		matched = append(matched, order)
+	}
+
+	offset, limit := paging(params)
+	orders := page(len(matched), offset, limit, func(i int) interface{} { return matched[i] })
+
+	return map[string]interface{}{"count": len(orders), "countTotal": len(matched), "orders": orders}, nil
+}
+
+func (s *Server) getOrder(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	id, err := strconv.ParseInt(params.Get("order_id"), 10, 64)
+	if err != nil {
+		return nil, invalidParameter("invalid order_id")
+	}
+
+	for _, order := range s.orders {
+		if order.OrderID == id {
+			return order, nil
+		}
+	}
+
+	return nil, invalidParameter("order %d not found", id)
+}
+
+func (s *Server) getOrderItems(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	id, err := strconv.ParseInt(params.Get("order_id"), 10, 64)
+	if err != nil {
+		return nil, invalidParameter("invalid order_id")
+	}
+
+	items, ok := s.orderItems[id]
+	if !ok {
+		return nil, invalidParameter("order %d not found", id)
+	}
+
+	return items, nil
+}
+
+func (s *Server) getMultipleOrderItems(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	out := []*lazada.MultipleOrderItems{}
+	for _, raw := range parseList(params.Get("order_ids")) {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, invalidParameter("invalid order id %s", raw)
+		}
+
+		out = append(out, &lazada.MultipleOrderItems{OrderID: id, OrderNumber: id, OrderItems: s.orderItems[id]})
+	}
+
+	return out, nil
+}
+
+// setStatus moves the order items to status and returns the fulfilment response for them
+func (s *Server) setStatus(params url.Values, status, provider, tracking string) (interface{}, *lazada.ErrorResponse) {
+	items := []*lazada.FulfilledOrderItem{}
+	for _, raw := range parseList(params.Get("order_item_ids")) {
+		id, _ := strconv.ParseInt(raw, 10, 64)
+		item := s.findOrderItem(id)
+		if item == nil {
+			return nil, invalidParameter("order item %s not found", raw)
+		}
+
+		item.Status = status
+		item.ShipmentProvider = provider
+		if tracking != "" {
+			item.TrackingCode = tracking
+		}
+		s.updateStatuses(item.OrderID)
+
+		items = append(items, &lazada.FulfilledOrderItem{
+			OrderItemID:      id,
+			PackageID:        fmt.Sprintf("PKG%d", item.OrderID),
+			ShipmentProvider: provider,
+			TrackingNumber:   item.TrackingCode,
+		})
+	}
+
+	return map[string]interface{}{"order_items": items}, nil
+}
+
+func (s *Server) packOrder(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	provider := params.Get("shipping_provider")
+	if provider == "" {
+		return nil, &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "shipping_provider is required"}
+	}
+
+	return s.setStatus(params, "packed", provider, fmt.Sprintf("TEST%d", s.requestID))
+}
+
+func (s *Server) readyToShip(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	return s.setStatus(params, "ready_to_ship", params.Get("shipment_provider"), params.Get("tracking_number"))
+}
+
+func (s *Server) cancelOrder(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	id, _ := strconv.ParseInt(params.Get("order_item_id"), 10, 64)
+	item := s.findOrderItem(id)
+	if item == nil {
+		return nil, invalidParameter("order item %d not found", id)
+	}
+
+	item.Status = "canceled"
+	item.Reason = params.Get("reason_id")
+	item.ReasonDetail = params.Get("reason_detail")
+	s.updateStatuses(item.OrderID)
+
+	return nil, nil
+}
+
+func (s *Server) getFailureReasons(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	return s.reasons, nil
+}
+
+func (s *Server) getDocument(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	docType := params.Get("doc_type")
+	html := fmt.Sprintf("<html><body>%s %s</body></html>", docType, params.Get("order_item_ids"))
+
+	return map[string]interface{}{"document": map[string]string{
+		"document_type": docType,
+		"mime_type":     "text/html",
+		"file":          base64.StdEncoding.EncodeToString([]byte(html)),
+	}}, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
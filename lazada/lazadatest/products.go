@@ -0,0 +1,474 @@
+package lazadatest
+
+import (
+	"encoding/xml"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Teddy-Schmitz/go-lazada/lazada"
+	"github.com/shopspring/decimal"
+)
+
+// AddBrands adds brands returned by the brands API
+func (s *Server) AddBrands(brands ...*lazada.Brand) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.brands = append(s.brands, brands...)
+}
+
+// SetCategoryTree sets the tree returned by the category tree API
+func (s *Server) SetCategoryTree(tree []*lazada.CategoryTree) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.categories = tree
+}
+
+// SetCategoryAttributes sets the attributes returned for a category
+func (s *Server) SetCategoryAttributes(categoryID int, attrs []*lazada.CategoryAttributes) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attributes[categoryID] = attrs
+}
+
+// AddProduct adds a product as if it had been created, an item id is assigned if it has none
+func (s *Server) AddProduct(p *lazada.GetProduct) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p.ItemID == 0 {
+		s.nextItemID++
+		p.ItemID = s.nextItemID
+	}
+
+	s.products = append(s.products, p)
+}
+
+// Products returns all the products the server knows about
+func (s *Server) Products() []*lazada.GetProduct {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*lazada.GetProduct{}, s.products...)
+}
+
+func (s *Server) getBrands(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	offset, limit := paging(params)
+	return page(len(s.brands), offset, limit, func(i int) interface{} { return s.brands[i] }), nil
+}
+
+// queryBrands pages through the brands whose name contains the name parameter
+func (s *Server) queryBrands(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	start, _ := strconv.Atoi(params.Get("startRow"))
+	size, _ := strconv.Atoi(params.Get("pageSize"))
+	if size <= 0 || size > 200 {
+		return nil, invalidParameter("pageSize must be between 1 and 200")
+	}
+
+	name := strings.ToLower(params.Get("name"))
+	matched := []*lazada.Brand{}
+	for _, b := range s.brands {
+		if strings.Contains(strings.ToLower(b.Name), name) {
+			matched = append(matched, b)
+		}
+	}
+
+	return map[string]interface{}{
+		"module":       page(len(matched), start, size, func(i int) interface{} { return matched[i] }),
+		"total_record": len(matched),
+		"start_row":    start,
+		"page_size":    size,
+	}, nil
+}
+
+func (s *Server) getCategoryTree(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	if s.categories == nil {
+		return []*lazada.CategoryTree{}, nil
+	}
+
+	return s.categories, nil
+}
+
+func (s *Server) getCategoryAttributes(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	id, err := strconv.Atoi(params.Get("primary_category_id"))
+	if err != nil {
+		return nil, invalidParameter("invalid primary_category_id")
+	}
+
+	attrs, ok := s.attributes[id]
+	if !ok {
+		return []*lazada.CategoryAttributes{}, nil
+	}
+
+	return attrs, nil
+}
+
+// suggestCategory suggests the leaf categories that share a word with the product name
+func (s *Server) suggestCategory(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	name := strings.Fields(strings.ToLower(params.Get("product_name")))
+	if len(name) == 0 {
+		return nil, invalidParameter("product_name is required")
+	}
+
+	ix := lazada.NewCategoryIndex(s.categories)
+	suggestions := []*lazada.CategorySuggestion{}
+	for _, c := range ix.Leaves() {
+		if !sharesWord(strings.Fields(strings.ToLower(c.Name)), name) {
+			continue
+		}
+
+		names := []string{}
+		for _, p := range ix.Path(c.CategoryID) {
+			names = append(names, p.Name)
+		}
+
+		suggestions = append(suggestions, &lazada.CategorySuggestion{
+			CategoryID:   c.CategoryID,
+			CategoryName: c.Name,
+			CategoryPath: strings.Join(names, ">"),
+		})
+	}
+
+	return map[string]interface{}{"categorySuggestions": suggestions}, nil
+}
+
+func sharesWord(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *Server) getProducts(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	skus := map[string]bool{}
+	for _, sku := range parseList(params.Get("sku_seller_list")) {
+		skus[sku] = true
+	}
+	search := strings.ToLower(params.Get("search"))
+
+	matched := []*lazada.GetProduct{}
+	for _, p := range s.products {
+		if len(skus) > 0 && !hasSKU(p, skus) {
+			continue
+		}
+
+		if search != "" && !strings.Contains(strings.ToLower(p.Attributes["name"]), search) && !hasSKU(p, map[string]bool{search: true}) {
+			continue
+		}
+
+		matched = append(matched, p)
+	}
+
+	offset, limit := paging(params)
+	products := page(len(matched), offset, limit, func(i int) interface{} { return matched[i] })
+
+	return map[string]interface{}{"total_products": len(matched), "products": products}, nil
+}
+
+func hasSKU(p *lazada.GetProduct, skus map[string]bool) bool {
+	for _, sku := range p.SKUs {
+		if skus[sku.SellerSKU] || skus[strings.ToLower(sku.SellerSKU)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) createProduct(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	req, errResp := parsePayload(params)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	prodNode := req.child("Product")
+	if prodNode == nil {
+		return nil, invalidParameter("missing Product")
+	}
+
+	category, _ := strconv.Atoi(prodNode.text("PrimaryCategory"))
+	if category == 0 {
+		return nil, &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "PrimaryCategory is required"}
+	}
+
+	s.nextItemID++
+	p := &lazada.GetProduct{ItemID: s.nextItemID, PrimaryCategory: category, Attributes: map[string]string{}}
+	if attrs := prodNode.child("Attributes"); attrs != nil {
+		for _, n := range attrs.Nodes {
+			p.Attributes[n.XMLName.Local] = n.Content
+		}
+	}
+
+	resp := &lazada.CreateProductResponse{ItemID: int64(p.ItemID)}
+	for _, skuNode := range prodNode.all("Skus", "Sku") {
+		s.nextSkuID++
+		sku := &lazada.ProductSKU{SkuID: s.nextSkuID, Status: "active"}
+		applySKU(sku, skuNode)
+		p.SKUs = append(p.SKUs, sku)
+
+		resp.SKUList = append(resp.SKUList, lazada.SKUItem{
+			SellerSKU: sku.SellerSKU,
+			ShopSKU:   sku.ShopSKU,
+			SKUID:     strconv.Itoa(sku.SkuID),
+		})
+	}
+
+	s.products = append(s.products, p)
+	return resp, nil
+}
+
+func (s *Server) updateProduct(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	req, errResp := parsePayload(params)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	prodNode := req.child("Product")
+	if prodNode == nil {
+		return nil, invalidParameter("missing Product")
+	}
+
+	for _, skuNode := range prodNode.all("Skus", "Sku") {
+		p, sku := s.findSKU(skuNode.text("SellerSku"))
+		if sku == nil {
+			return nil, &lazada.ErrorResponse{Code: "208", Type: "ISV", Message: "SellerSku not found",
+				Detail: []*lazada.ErrorDetails{{Field: "SellerSku", Message: "not found", SellerSKU: skuNode.text("SellerSku")}}}
+		}
+
+		applySKU(sku, skuNode)
+
+		if attrs := prodNode.child("Attributes"); attrs != nil {
+			for _, n := range attrs.Nodes {
+				p.Attributes[n.XMLName.Local] = n.Content
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Server) updatePriceQuantity(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	req, errResp := parsePayload(params)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	// Known skus are updated even if others are rejected, like the platform does
+	var detail []*lazada.ErrorDetails
+	for _, skuNode := range req.all("Product", "Skus", "Sku") {
+		_, sku := s.findSKU(skuNode.text("SellerSku"))
+		if sku == nil {
+			detail = append(detail, &lazada.ErrorDetails{Field: "SellerSku", Message: "not found", SellerSKU: skuNode.text("SellerSku")})
+			continue
+		}
+
+		applySKU(sku, skuNode)
+	}
+
+	if len(detail) > 0 {
+		return nil, &lazada.ErrorResponse{Code: "208", Type: "ISV", Message: "SellerSku not found", Detail: detail}
+	}
+
+	return nil, nil
+}
+
+// findSKU returns the product and sku with the seller sku
+func (s *Server) findSKU(sellerSKU string) (*lazada.GetProduct, *lazada.ProductSKU) {
+	for _, p := range s.products {
+		for _, sku := range p.SKUs {
+			if sku.SellerSKU == sellerSKU {
+				return p, sku
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// applySKU copies the fields of a Sku element onto sku
+func applySKU(sku *lazada.ProductSKU, n *node) {
+	for _, child := range n.Nodes {
+		v := child.Content
+		switch strings.ToLower(child.XMLName.Local) {
+		case "sellersku":
+			sku.SellerSKU = v
+			sku.ShopSKU = v + "-shop"
+		case "quantity":
+			sku.Quantity, _ = strconv.Atoi(v)
+			sku.Available = sku.Quantity
+		case "price":
+			sku.Price, _ = decimal.NewFromString(v)
+		case "special_price", "saleprice":
+			sku.SpecialPrice, _ = decimal.NewFromString(v)
+		case "special_from_date", "salestartdate":
+			sku.SpecialFromTime = v
+		case "special_to_date", "saleenddate":
+			sku.SpecialToDate = v
+		case "package_weight":
+			sku.PackageWeight = v
+		case "package_length":
+			sku.PackageLength = v
+		case "package_width":
+			sku.PackageWidth = v
+		case "package_height":
+			sku.PackageHeight = v
+		case "images":
+			sku.Images = nil
+			for _, img := range child.Nodes {
+				sku.Images = append(sku.Images, img.Content)
+			}
+		}
+	}
+}
+
+func (s *Server) uploadImage(params url.Values, files map[string][]*multipart.FileHeader) (interface{}, *lazada.ErrorResponse) {
+	if len(files["image"]) == 0 {
+		return nil, &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "image is required"}
+	}
+
+	f := files["image"][0]
+	if f.Size == 0 {
+		return nil, &lazada.ErrorResponse{Code: "InvalidParameter", Type: "ISV", Message: "image is empty"}
+	}
+
+	resp := &lazada.ImageResponse{}
+	resp.Image.URL = "https://sg-test.slatic.net/original/" + strconv.FormatInt(f.Size, 10) + path.Ext(f.Filename)
+	resp.Image.HashCode = strconv.FormatInt(f.Size, 10)
+	return resp, nil
+}
+
+func (s *Server) migrateImage(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	req, errResp := parsePayload(params)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	u := req.text("Image", "Url")
+	if u == "" {
+		return nil, &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "Url is required"}
+	}
+
+	resp := &lazada.ImageResponse{}
+	resp.Image.URL = "https://sg-test.slatic.net/original/" + strconv.Itoa(len(u)) + ".jpg"
+	resp.Image.HashCode = strconv.Itoa(len(u))
+	return resp, nil
+}
+
+func (s *Server) migrateImages(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	req, errResp := parsePayload(params)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	result := &lazada.ImageBatchResult{}
+	for _, n := range req.all("Images", "Url") {
+		u := strings.TrimSpace(n.Content)
+		result.Images = append(result.Images, &lazada.MigratedImage{
+			URL:      "https://sg-test.slatic.net/original/" + strconv.Itoa(len(u)) + ".jpg",
+			HashCode: strconv.Itoa(len(u)),
+		})
+	}
+
+	// Batches are done straight away
+	id := s.newRequestID()
+	s.batches[id] = result
+
+	return map[string]string{"batch_id": id}, nil
+}
+
+func (s *Server) getImageResponse(params url.Values) (interface{}, *lazada.ErrorResponse) {
+	result, ok := s.batches[params.Get("batch_id")]
+	if !ok {
+		return nil, invalidParameter("batch %s not found", params.Get("batch_id"))
+	}
+
+	return result, nil
+}
+
+// node is a generic XML element used to read payloads
+type node struct {
+	XMLName xml.Name
+	Content string  `xml:",chardata"`
+	Nodes   []*node `xml:",any"`
+}
+
+func (n *node) child(name string) *node {
+	for _, c := range n.Nodes {
+		if c.XMLName.Local == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// all returns all the elements matching the path
+func (n *node) all(path ...string) []*node {
+	current := []*node{n}
+	for _, name := range path {
+		next := []*node{}
+		for _, c := range current {
+			for _, child := range c.Nodes {
+				if child.XMLName.Local == name {
+					next = append(next, child)
+				}
+			}
+		}
+		current = next
+	}
+
+	return current
+}
+
+// text returns the content of the first element matching the path
+func (n *node) text(path ...string) string {
+	if found := n.all(path...); len(found) > 0 {
+		return strings.TrimSpace(found[0].Content)
+	}
+
+	return ""
+}
+
+func parsePayload(params url.Values) (*node, *lazada.ErrorResponse) {
+	payload := params.Get("payload")
+	if payload == "" {
+		return nil, &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "payload is required"}
+	}
+
+	n := &node{}
+	if err := xml.Unmarshal([]byte(payload), n); err != nil {
+		return nil, invalidParameter("invalid payload: %v", err)
+	}
+
+	return n, nil
+}
+
+func paging(params url.Values) (offset, limit int) {
+	offset, _ = strconv.Atoi(params.Get("offset"))
+	limit, _ = strconv.Atoi(params.Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+
+	return offset, limit
+}
+
+// page returns the items of a list of length n between offset and offset+limit
+func page(n, offset, limit int, item func(i int) interface{}) []interface{} {
+	out := []interface{}{}
+	for i := offset; i < n && i < offset+limit; i++ {
+		out = append(out, item(i))
+	}
+
+	return out
+}
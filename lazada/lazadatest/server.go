@@ -0,0 +1,299 @@
+// Package lazadatest provides an in process fake of the Lazada open platform for testing.
+//
+// The server checks the signature of every request with the same algorithm as the real platform,
+// keeps products, brands, categories and orders in memory, and can be told to fail calls with any error code.
+package lazadatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Teddy-Schmitz/go-lazada/lazada"
+)
+
+// Server is a fake open platform listening on a local address
+type Server struct {
+	// The URL of the server, use it with Client.SetBaseURL
+	URL string
+
+	AppKey string
+	Secret string
+
+	server *httptest.Server
+	signer *lazada.Client
+	mux    *http.ServeMux
+
+	mu         sync.Mutex
+	tokens     map[string]bool
+	refresh    map[string]bool
+	failures   map[string][]failure
+	requestID  int
+	brands     []*lazada.Brand
+	categories []*lazada.CategoryTree
+	attributes map[int][]*lazada.CategoryAttributes
+	products   []*lazada.GetProduct
+	nextItemID int
+	nextSkuID  int
+	orders     []*lazada.Order
+	orderItems map[int64][]*lazada.OrderItem
+	reasons    []*lazada.Reason
+	batches    map[string]*lazada.ImageBatchResult
+}
+
+type failure struct {
+	code    string
+	message string
+	detail  []*lazada.ErrorDetails
+}
+
+// handlerFunc handles a verified call and returns the data of the response or an error response.
+// Handlers are called with the server lock held.
+type handlerFunc func(params url.Values) (interface{}, *lazada.ErrorResponse)
+
+// uploadHandlerFunc is a handlerFunc for apis taking files in a multipart form
+type uploadHandlerFunc func(params url.Values, files map[string][]*multipart.FileHeader) (interface{}, *lazada.ErrorResponse)
+
+// maxUploadMemory is how much of an upload is kept in memory, the rest goes to temporary files
+const maxUploadMemory = 32 << 20
+
+// NewServer starts a fake open platform accepting calls signed with the app key and secret
+func NewServer(appKey, secret string) *Server {
+	s := &Server{
+		AppKey:     appKey,
+		Secret:     secret,
+		mux:        http.NewServeMux(),
+		tokens:     make(map[string]bool),
+		refresh:    make(map[string]bool),
+		failures:   make(map[string][]failure),
+		attributes: make(map[int][]*lazada.CategoryAttributes),
+		orderItems: make(map[int64][]*lazada.OrderItem),
+		batches:    make(map[string]*lazada.ImageBatchResult),
+		nextItemID: 1000,
+		nextSkuID:  5000,
+		reasons: []*lazada.Reason{
+			{ReasonID: 1, Name: "Out of stock", Type: "canceled"},
+			{ReasonID: 2, Name: "Wrong price", Type: "canceled"},
+			{ReasonID: 3, Name: "Customer unreachable", Type: "failed"},
+		},
+	}
+
+	// The signer only computes signatures, its region is never used
+	s.signer, _ = lazada.NewClient(appKey, secret, lazada.Singapore)
+
+	s.routes()
+	s.server = httptest.NewServer(s.mux)
+	s.URL = s.server.URL + "/"
+
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a client for the server without an access token.
+// The token APIs of the client are also sent to the server.
+func (s *Server) Client(opts ...lazada.ClientOption) *lazada.Client {
+	opts = append([]lazada.ClientOption{lazada.WithBaseURL(s.URL), lazada.WithAuthURL(s.URL)}, opts...)
+
+	c, err := lazada.NewClient(s.AppKey, s.Secret, lazada.Singapore, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// AddAccessToken makes the server accept the token.
+// Until a token is added any access token is accepted.
+func (s *Server) AddAccessToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[token] = true
+}
+
+// Fail makes the next times calls to the api path, e.g. "/products/get", fail with the error code.
+// Failures queue up so a call can be made to fail with different codes in a row.
+func (s *Server) Fail(api, code, message string, times int) {
+	s.FailWithDetail(api, code, message, times, nil)
+}
+
+// FailWithDetail is like Fail but includes field level error details in the response
+func (s *Server) FailWithDetail(api, code, message string, times int, detail []*lazada.ErrorDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < times; i++ {
+		s.failures[api] = append(s.failures[api], failure{code: code, message: message, detail: detail})
+	}
+}
+
+func (s *Server) routes() {
+	s.handle("/auth/token/create", false, s.createToken)
+	s.handle("/auth/token/refresh", false, s.refreshToken)
+	s.handle("/brands/get", false, s.getBrands)
+	s.handle("/category/brands/query", false, s.queryBrands)
+	s.handle("/category/tree/get", false, s.getCategoryTree)
+	s.handle("/category/attributes/get", false, s.getCategoryAttributes)
+	s.handle("/product/category/suggestion/get", true, s.suggestCategory)
+	s.handle("/products/get", true, s.getProducts)
+	s.handle("/product/create", true, s.createProduct)
+	s.handle("/product/update", true, s.updateProduct)
+	s.handle("/product/price_quantity/update", true, s.updatePriceQuantity)
+	s.handle("/product/remove", true, s.removeProduct)
+	s.handle("/product/sku/remove", true, s.removeSKU)
+	s.handle("/product/deactivate", true, s.deactivateProduct)
+	s.handle("/product/item/get", true, s.getItem)
+	s.handle("/product/qc/status/get", true, s.getQCStatus)
+	s.handleUpload("/image/upload", true, s.uploadImage)
+	s.handle("/image/migrate", true, s.migrateImage)
+	s.handle("/images/migrate", true, s.migrateImages)
+	s.handle("/image/response/get", true, s.getImageResponse)
+	s.handle("/orders/get", true, s.getOrders)
+	s.handle("/order/get", true, s.getOrder)
+	s.handle("/order/items/get", true, s.getOrderItems)
+	s.handle("/orders/items/get", true, s.getMultipleOrderItems)
+	s.handle("/order/pack", true, s.packOrder)
+	s.handle("/order/rts", true, s.readyToShip)
+	s.handle("/order/cancel", true, s.cancelOrder)
+	s.handle("/order/failure_reason/get", true, s.getFailureReasons)
+	s.handle("/order/document/get", true, s.getDocument)
+}
+
+// handle registers an api path, every call is verified before it reaches h
+func (s *Server) handle(api string, needsToken bool, h handlerFunc) {
+	s.handleUpload(api, needsToken, func(params url.Values, _ map[string][]*multipart.FileHeader) (interface{}, *lazada.ErrorResponse) {
+		return h(params)
+	})
+}
+
+// handleUpload is like handle but also passes the files of a multipart form to h,
+// files are not part of the signature
+func (s *Server) handleUpload(api string, needsToken bool, h uploadHandlerFunc) {
+	s.mux.HandleFunc("/rest"+api, func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			err = r.ParseMultipartForm(maxUploadMemory)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var files map[string][]*multipart.FileHeader
+		if r.MultipartForm != nil {
+			files = r.MultipartForm.File
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		params := r.Form
+		if errResp := s.verify(api, params, needsToken); errResp != nil {
+			s.writeError(w, errResp)
+			return
+		}
+
+		if queue := s.failures[api]; len(queue) > 0 {
+			f := queue[0]
+			s.failures[api] = queue[1:]
+			s.writeError(w, &lazada.ErrorResponse{Code: f.code, Type: "ISP", Message: f.message, Detail: f.detail})
+			return
+		}
+
+		data, errResp := h(params, files)
+		if errResp != nil {
+			s.writeError(w, errResp)
+			return
+		}
+
+		// The token APIs return the token at the top level instead of under data
+		if t, ok := data.(*lazada.Token); ok {
+			s.write(w, newTokenResponse(t, s.newRequestID()))
+			return
+		}
+
+		s.write(w, map[string]interface{}{"code": "0", "request_id": s.newRequestID(), "data": data})
+	})
+}
+
+// verify checks the system parameters and the signature the same way the platform does
+func (s *Server) verify(api string, params url.Values, needsToken bool) *lazada.ErrorResponse {
+	for _, p := range []string{"app_key", "timestamp", "sign_method", "sign"} {
+		if params.Get(p) == "" {
+			return &lazada.ErrorResponse{Code: "MissingParameter", Type: "ISV", Message: "missing " + p}
+		}
+	}
+
+	if params.Get("app_key") != s.AppKey {
+		return &lazada.ErrorResponse{Code: "InvalidApiKey", Type: "ISV", Message: "unknown app key"}
+	}
+
+	signed := url.Values{}
+	for k, v := range params {
+		if k != "sign" {
+			signed[k] = v
+		}
+	}
+
+	if s.signer.Signature(api, signed, nil) != params.Get("sign") {
+		return &lazada.ErrorResponse{Code: "IncompleteSignature", Type: "ISV", Message: "the request signature does not conform to platform standards"}
+	}
+
+	if !needsToken {
+		return nil
+	}
+
+	token := params.Get("access_token")
+	if token == "" || (len(s.tokens) > 0 && !s.tokens[token]) {
+		return &lazada.ErrorResponse{Code: "IllegalAccessToken", Type: "ISV", Message: "the specified access token is invalid or expired"}
+	}
+
+	return nil
+}
+
+func (s *Server) newRequestID() string {
+	s.requestID++
+	return fmt.Sprintf("test-%d", s.requestID)
+}
+
+func (s *Server) write(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, e *lazada.ErrorResponse) {
+	e.RequestID = s.newRequestID()
+	s.write(w, e)
+}
+
+func invalidParameter(format string, args ...interface{}) *lazada.ErrorResponse {
+	return &lazada.ErrorResponse{Code: "InvalidParameter", Type: "ISV", Message: fmt.Sprintf(format, args...)}
+}
+
+// parseList parses list parameters like ["a","b"] or [1,2]
+func parseList(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "[")
+	raw = strings.TrimSuffix(raw, "]")
+
+	out := []string{}
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.Trim(strings.TrimSpace(v), `"`)
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,220 @@
+package lazadatest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Teddy-Schmitz/go-lazada/lazada"
+	"github.com/Teddy-Schmitz/go-lazada/lazada/lazadatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Products(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	srv.AddAccessToken("token")
+	c := srv.Client().NewTokenClient("token")
+	ctx := context.Background()
+
+	resp, err := c.Products.Create(ctx, &lazada.Product{
+		PrimaryCategory: "10001958",
+		Attributes:      &lazada.Attributes{Attrs: lazada.StringMap{"name": "test product"}},
+		Skus: []*lazada.Sku{{
+			SellerSku: "sku-1",
+			SkuAttrs:  lazada.StringMap{"price": "23.0", "quantity": "4"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.SKUList, 1)
+
+	list := lazada.SliceString([]string{"sku-1"})
+	products, err := c.Products.Get(ctx, &lazada.SearchOptions{SKUSellerList: &list, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, products.TotalProducts)
+	assert.Equal(t, "test product", products.Products[0].Attributes["name"])
+	assert.Equal(t, "23", products.Products[0].SKUs[0].Price.String())
+	assert.Equal(t, 4, products.Products[0].SKUs[0].Quantity)
+
+	quantity := 10
+	result, err := c.Products.UpdatePriceQuantity(ctx, []*lazada.PriceQuantity{
+		{SellerSKU: "sku-1", Quantity: &quantity},
+		{SellerSKU: "missing", Quantity: &quantity},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sku-1"}, result.Updated)
+	assert.True(t, errors.Is(result.Failed["missing"], lazada.ErrSKUNotFound))
+	assert.Equal(t, 10, srv.Products()[0].SKUs[0].Quantity)
+
+	item, err := c.Products.GetItem(ctx, &lazada.GetItemOptions{SellerSKU: "sku-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int(resp.ItemID), item.ItemID)
+
+	require.NoError(t, c.Products.Deactivate(ctx, resp.ItemID))
+	assert.Equal(t, "inactive", srv.Products()[0].SKUs[0].Status)
+
+	qc, err := c.Products.GetQCStatus(ctx, []string{"sku-1"})
+	require.NoError(t, err)
+	require.Len(t, qc, 1)
+
+	require.NoError(t, c.Products.Remove(ctx, []string{"sku-1"}))
+	assert.Empty(t, srv.Products())
+}
+
+func TestServer_MigrateImages(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	c := srv.Client().NewTokenClient("token")
+	result, err := c.Products.MigrateImages(context.Background(), []string{"https://example.com/a.jpg"})
+	require.NoError(t, err)
+	assert.Len(t, result.Images, 1)
+}
+
+func TestServer_UploadImage(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	srv.AddAccessToken("token")
+	c := srv.Client().NewTokenClient("token")
+
+	img, err := c.Products.UploadImage(context.Background(), strings.NewReader("not really a jpeg"), "shirt.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sg-test.slatic.net/original/17.jpg", img.Image.URL)
+	assert.Equal(t, "17", img.Image.HashCode)
+
+	_, err = srv.Client().NewTokenClient("other").Products.UploadImage(context.Background(), strings.NewReader("x"), "x.jpg")
+	assert.True(t, errors.Is(err, lazada.ErrInvalidToken))
+}
+
+func TestServer_SuggestCategories(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	srv.SetCategoryTree([]*lazada.CategoryTree{{CategoryID: 1, Name: "Fashion", Children: []*lazada.CategoryTree{
+		{CategoryID: 2, Name: "Shirts", Leaf: true},
+		{CategoryID: 3, Name: "Shoes", Leaf: true},
+	}}})
+	srv.AddAccessToken("token")
+
+	c := srv.Client().NewTokenClient("token")
+	suggestions, err := c.Products.SuggestCategories(context.Background(), "Red Shirts")
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, 2, suggestions[0].CategoryID)
+	assert.Equal(t, "Fashion>Shirts", suggestions[0].CategoryPath)
+}
+
+func TestServer_Brands(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	for i := 1; i <= 25; i++ {
+		srv.AddBrands(&lazada.Brand{BrandID: i, Name: fmt.Sprintf("Brand %d", i), GlobalIdentifier: fmt.Sprintf("brand_%d", i)})
+	}
+
+	c := srv.Client()
+	ctx := context.Background()
+
+	ix, err := c.Products.BrandIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 25, ix.Len())
+
+	b, ok := ix.Lookup("brand_12")
+	require.True(t, ok)
+	assert.Equal(t, 12, b.BrandID)
+
+	page, err := c.Products.QueryBrands(ctx, &lazada.BrandQueryOptions{PageSize: 5, Name: "brand 2"})
+	require.NoError(t, err)
+	assert.Equal(t, 7, page.Total)
+	assert.Len(t, page.Brands, 5)
+}
+
+func TestServer_Verification(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	srv.AddAccessToken("token")
+	ctx := context.Background()
+
+	// Signed with the wrong secret
+	bad, err := lazada.NewClient("123456", "wrongsecret", lazada.Singapore, lazada.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	_, err = bad.Products.Brands(ctx, nil)
+	assert.True(t, errors.Is(err, lazada.ErrInvalidSignature))
+
+	_, err = srv.Client().NewTokenClient("unknown").Products.Get(ctx, nil)
+	assert.True(t, errors.Is(err, lazada.ErrInvalidToken))
+}
+
+func TestServer_Auth(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	srv.AddAccessToken("token")
+	c := srv.Client()
+	ctx := context.Background()
+
+	tok, err := c.Auth.Exchange(ctx, "code")
+	require.NoError(t, err)
+	require.NotEmpty(t, tok.AccessToken)
+	assert.True(t, tok.Valid())
+
+	_, err = c.NewTokenClient(tok.AccessToken).Products.Get(ctx, nil)
+	require.NoError(t, err)
+
+	refreshed, err := c.Auth.Refresh(ctx, tok.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, tok.AccessToken, refreshed.AccessToken)
+	assert.True(t, refreshed.Valid())
+
+	_, err = c.Auth.Refresh(ctx, tok.RefreshToken)
+	assert.True(t, errors.Is(err, lazada.ErrInvalidRefreshToken))
+}
+
+func TestServer_Fail(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	srv.AddBrands(&lazada.Brand{BrandID: 1, Name: "Kid Basix"})
+	srv.Fail("/brands/get", "ApiCallLimit", "slow down", 1)
+	c := srv.Client()
+
+	_, err := c.Products.Brands(context.Background(), nil)
+	assert.True(t, errors.Is(err, lazada.ErrCallLimit))
+
+	brands, err := c.Products.Brands(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, brands, 1)
+}
+
+func TestServer_Orders(t *testing.T) {
+	srv := lazadatest.NewServer("123456", "testsecret")
+	defer srv.Close()
+
+	srv.AddOrder(&lazada.Order{OrderID: 1}, &lazada.OrderItem{OrderItemID: 10}, &lazada.OrderItem{OrderItemID: 11})
+	c := srv.Client().NewTokenClient("token")
+	ctx := context.Background()
+
+	status := "pending"
+	orders, err := c.Orders.GetOrders(ctx, &lazada.OrderSearchOptions{Status: &status, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, orders.Orders, 1)
+
+	packed, err := c.Orders.Pack(ctx, &lazada.PackOptions{OrderItemIDs: []int64{10, 11}, ShippingProvider: "LEX"})
+	require.NoError(t, err)
+	require.Len(t, packed, 2)
+
+	_, err = c.Orders.ReadyToShip(ctx, &lazada.ReadyToShipOptions{OrderItemIDs: []int64{10, 11},
+		ShipmentProvider: "LEX", TrackingNumber: packed[0].TrackingNumber})
+	require.NoError(t, err)
+	assert.Equal(t, "ready_to_ship", srv.OrderItem(10).Status)
+
+	reasons, err := c.Orders.CancelReasons(ctx)
+	require.NoError(t, err)
+	assert.Len(t, reasons, 2)
+}
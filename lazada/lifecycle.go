@@ -0,0 +1,118 @@
+package lazada
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// MaxRemoveSKUs is the most seller skus Remove takes at once
+const MaxRemoveSKUs = 50
+
+// Remove deletes the products that the seller skus belong to
+// At least one and at most MaxRemoveSKUs skus can be removed at once
+// Requires a client access token
+func (p *ProductService) Remove(ctx context.Context, sellerSKUs []string) error {
+	if len(sellerSKUs) == 0 {
+		return errors.New("no skus to remove")
+	}
+
+	if len(sellerSKUs) > MaxRemoveSKUs {
+		return fmt.Errorf("at most %d skus can be removed at once", MaxRemoveSKUs)
+	}
+
+	params := url.Values{"seller_sku_list": {SliceString(sellerSKUs)}}
+
+	_, err := p.client.CallAPI(ctx, "RemoveProduct", params, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type deactivateRequest struct {
+	XMLName    xml.Name `xml:"Request"`
+	ItemID     int64    `xml:"Product>ItemId"`
+	SellerSKUs []string `xml:"Product>Skus>SellerSku,omitempty"`
+}
+
+// Deactivate sets a product inactive so it is no longer shown to buyers
+// If seller skus are given only those skus are deactivated, otherwise the whole product is
+// Requires a client access token
+func (p *ProductService) Deactivate(ctx context.Context, itemID int64, sellerSKUs ...string) error {
+	_, err := p.client.CallAPI(ctx, "DeactivateProduct", nil, &deactivateRequest{ItemID: itemID, SellerSKUs: sellerSKUs}, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveSKU deletes a single sku from a product, the product itself is kept
+// Requires a client access token
+func (p *ProductService) RemoveSKU(ctx context.Context, itemID, skuID int64) error {
+	// The api identifies skus as SkuId_{item id}_{sku id}
+	params := url.Values{"seller_sku_list": {SliceString([]string{fmt.Sprintf("SkuId_%d_%d", itemID, skuID)})}}
+
+	_, err := p.client.CallAPI(ctx, "RemoveSKU", params, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetItemOptions selects the product returned by GetItem, one of the fields must be set
+type GetItemOptions struct {
+	ItemID    int64  `url:"item_id,omitempty"`
+	SellerSKU string `url:"seller_sku,omitempty"`
+}
+
+// GetItem returns a single product by its item id or one of its seller skus
+// Requires a client access token
+func (p *ProductService) GetItem(ctx context.Context, opts *GetItemOptions) (*GetProduct, error) {
+	if opts == nil || (opts.ItemID == 0 && opts.SellerSKU == "") {
+		return nil, errors.New("an item id or seller sku is required")
+	}
+
+	product := &GetProduct{}
+	_, err := p.client.CallAPI(ctx, "GetItem", opts, nil, product)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// QCStatus is the quality control state of a sku after it was created or updated
+type QCStatus struct {
+	SellerSKU string `json:"seller_sku"`
+
+	// One of "pending", "approved" or "rejected"
+	Status string `json:"status"`
+
+	// Why the sku was rejected
+	Reason string `json:"reason"`
+}
+
+// GetQCStatus returns the quality control state of the seller skus
+// Requires a client access token
+func (p *ProductService) GetQCStatus(ctx context.Context, sellerSKUs []string) ([]*QCStatus, error) {
+	params := url.Values{
+		"seller_skus": {SliceString(sellerSKUs)},
+		"offset":      {"0"},
+		"limit":       {strconv.Itoa(len(sellerSKUs))},
+	}
+
+	statuses := []*QCStatus{}
+	_, err := p.client.CallAPI(ctx, "GetQCStatus", params, nil, &statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,74 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductService_RemoveAndDeactivate(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/product/remove", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, `["a","b"]`, r.URL.Query().Get("seller_sku_list"))
+		fmt.Fprint(w, `{"code":"0"}`)
+	})
+
+	mux.HandleFunc("/rest/product/sku/remove", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `["SkuId_10_20"]`, r.URL.Query().Get("seller_sku_list"))
+		fmt.Fprint(w, `{"code":"0"}`)
+	})
+
+	mux.HandleFunc("/rest/product/deactivate", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Contains(t, r.PostForm.Get("payload"),
+			"<Product><ItemId>10</ItemId><Skus><SellerSku>a</SellerSku></Skus></Product>")
+		fmt.Fprint(w, `{"code":"0"}`)
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.Products.Remove(ctx, []string{"a", "b"}))
+	assert.EqualError(t, client.Products.Remove(ctx, nil), "no skus to remove")
+	assert.Error(t, client.Products.Remove(ctx, make([]string, MaxRemoveSKUs+1)))
+	require.NoError(t, client.Products.RemoveSKU(ctx, 10, 20))
+	require.NoError(t, client.Products.Deactivate(ctx, 10, "a"))
+}
+
+func TestProductService_GetItem(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/product/item/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "sku-1", r.URL.Query().Get("seller_sku"))
+		assert.Empty(t, r.URL.Query().Get("item_id"))
+		fmt.Fprint(w, `{"code":"0","data":{"item_id":10,"primary_category":3,"attributes":{"name":"test"},
+			"skus":[{"SellerSku":"sku-1","price":"9.90"}]}}`)
+	})
+
+	mux.HandleFunc("/rest/product/qc/status/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `["sku-1"]`, r.URL.Query().Get("seller_skus"))
+		fmt.Fprint(w, `{"code":"0","data":[{"seller_sku":"sku-1","status":"rejected","reason":"blurry image"}]}`)
+	})
+
+	ctx := context.Background()
+	item, err := client.Products.GetItem(ctx, &GetItemOptions{SellerSKU: "sku-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 10, item.ItemID)
+	assert.Equal(t, "test", item.Attributes["name"])
+	assert.Equal(t, "9.9", item.SKUs[0].Price.String())
+
+	_, err = client.Products.GetItem(ctx, &GetItemOptions{})
+	assert.Error(t, err)
+
+	statuses, err := client.Products.GetQCStatus(ctx, []string{"sku-1"})
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "rejected", statuses[0].Status)
+	assert.Equal(t, "blurry image", statuses[0].Reason)
+}
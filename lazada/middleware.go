@@ -0,0 +1,123 @@
+package lazada
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// redacted replaces the value of secret parameters returned by Call.Params
+const redacted = "REDACTED"
+
+// Call is a single attempt at calling an api as seen by middleware
+type Call struct {
+	// API is the name of the api from apiNames, e.g. "GetProducts", or the path if the api is unknown
+	API string
+
+	// Attempt is the number of the attempt starting from 1, it goes up when a call is retried
+	Attempt int
+
+	// Request is the signed http request, middleware can add headers or change it before calling next.
+	// If the parameters are changed the request must be signed again with Sign.
+	Request *http.Request
+
+	client *Client
+	token  string
+}
+
+// CallHandler sends a call and returns the http response, the decoded response and any error.
+// The body of the http response has already been read.
+type CallHandler func(call *Call) (*http.Response, *LazadaResponse, error)
+
+// Middleware wraps a CallHandler, it can run code before and after next and must call next to send the call.
+// It is called once for every attempt of a call.
+type Middleware func(next CallHandler) CallHandler
+
+// Use adds middleware to the client, the first middleware added is the outermost.
+// Clients returned by NewTokenClient and the other copy methods keep the middleware added before they were made.
+func (c *Client) Use(mw ...Middleware) {
+	// Copy so clients sharing the old slice are not changed
+	c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], mw...)
+}
+
+// WithMiddleware adds middleware to the client, see Client.Use
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) error {
+		c.Use(mw...)
+		return nil
+	}
+}
+
+// Params returns the signed parameters of the call with the access token and the signature redacted
+func (c *Call) Params() (url.Values, error) {
+	params := c.Request.URL.Query()
+
+	if hasFormBody(c.Request) {
+		body, err := c.Request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "cant read body")
+		}
+
+		params, err = url.ParseQuery(string(data))
+		if err != nil {
+			return nil, errors.Wrap(err, "cant parse body")
+		}
+	}
+
+	for _, k := range []string{"access_token", "sign"} {
+		if params.Get(k) != "" {
+			params.Set(k, redacted)
+		}
+	}
+
+	return params, nil
+}
+
+// Sign signs the request again with a fresh timestamp, use it after changing the parameters of the request
+func (c *Call) Sign() error {
+	return c.client.sign(c.Request, c.token)
+}
+
+// handler returns the handler for a call with the middleware of the client applied
+func (c *Client) handler() CallHandler {
+	h := c.send
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+
+	return h
+}
+
+// send is the innermost handler, it sends the request and checks the response
+func (c *Client) send(call *Call) (*http.Response, *LazadaResponse, error) {
+	ctx := call.Request.Context()
+
+	resp, err := c.client.Do(call.Request)
+	if err != nil {
+		// If the context was cancelled its error is more useful than the transport one
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	lazResp, err := CheckResponse(resp)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		return resp, nil, err
+	}
+
+	return resp, lazResp, nil
+}
@@ -0,0 +1,96 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "trace-1", r.Header.Get("X-Trace-Id"))
+		fmt.Fprint(w, `{"code":"0","request_id":"req-1","data":[]}`)
+	})
+
+	var order []string
+	var seen *Call
+	var seenResp *LazadaResponse
+	client.Use(func(next CallHandler) CallHandler {
+		return func(call *Call) (*http.Response, *LazadaResponse, error) {
+			order = append(order, "outer")
+			seen = call
+			resp, lazResp, err := next(call)
+			seenResp = lazResp
+			return resp, lazResp, err
+		}
+	}, func(next CallHandler) CallHandler {
+		return func(call *Call) (*http.Response, *LazadaResponse, error) {
+			order = append(order, "inner")
+			call.Request.Header.Set("X-Trace-Id", "trace-1")
+			return next(call)
+		}
+	})
+
+	// Copies made after Use keep the middleware
+	_, err := client.NewTokenClient("othertoken").Products.Brands(context.Background(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"outer", "inner"}, order)
+	assert.Equal(t, "GetBrands", seen.API)
+	assert.Equal(t, 1, seen.Attempt)
+	assert.Equal(t, "req-1", seenResp.RequestID)
+
+	params, err := seen.Params()
+	require.NoError(t, err)
+	assert.Equal(t, redacted, params.Get("access_token"))
+	assert.Equal(t, redacted, params.Get("sign"))
+	assert.Equal(t, "123456", params.Get("app_key"))
+
+	// The original request still carries the real values
+	assert.Equal(t, "othertoken", seen.Request.URL.Query().Get("access_token"))
+}
+
+func TestClient_MiddlewareError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/product/create", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":"InvalidParameter","type":"ISV","message":"bad","request_id":"req-2"}`)
+	})
+
+	var callErr error
+	var params string
+	client.Use(func(next CallHandler) CallHandler {
+		return func(call *Call) (*http.Response, *LazadaResponse, error) {
+			p, err := call.Params()
+			require.NoError(t, err)
+			params = p.Get("payload")
+
+			resp, lazResp, err := next(call)
+			callErr = err
+			return resp, lazResp, err
+		}
+	})
+
+	// Middleware added to a copy does not change the original client
+	other := client.NewTokenClient("othertoken")
+	other.Use(func(next CallHandler) CallHandler {
+		return func(call *Call) (*http.Response, *LazadaResponse, error) {
+			t.Fatal("middleware of a copy must not be used")
+			return nil, nil, nil
+		}
+	})
+
+	_, err := client.Products.Create(context.Background(), &Product{PrimaryCategory: "1"})
+	require.Error(t, err)
+	assert.Equal(t, err, callErr)
+	assert.Equal(t, "req-2", callErr.(*ErrorResponse).RequestID)
+	assert.Contains(t, params, "<PrimaryCategory>1</PrimaryCategory>")
+}
@@ -0,0 +1,248 @@
+package lazada
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidState is returned when the state of an OAuth callback was not issued by the handler or was already used
+var ErrInvalidState = errors.New("lazada: invalid oauth state")
+
+// OAuthError is returned when the open platform redirects back with an error instead of a code,
+// for example because the seller declined the authorization
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return fmt.Sprintf("lazada: oauth error %s: %s", e.Code, e.Description)
+}
+
+// StateStore keeps track of the state values of OAuth flows that have been started
+type StateStore interface {
+	// Save remembers a newly issued state
+	Save(ctx context.Context, state string) error
+
+	// Consume reports if the state was issued and forgets it so it can only be used once
+	Consume(ctx context.Context, state string) (bool, error)
+}
+
+// MemoryStateStore keeps states in memory for a limited time, it is safe for concurrent use
+type MemoryStateStore struct {
+	ttl time.Duration
+
+	mu     sync.Mutex
+	states map[string]time.Time
+}
+
+// NewMemoryStateStore returns a store where states expire after ttl
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	return &MemoryStateStore{ttl: ttl, states: make(map[string]time.Time)}
+}
+
+// Save implements StateStore
+func (s *MemoryStateStore) Save(ctx context.Context, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Clean up expired states so abandoned flows dont pile up
+	now := time.Now()
+	for st, exp := range s.states {
+		if now.After(exp) {
+			delete(s.states, st)
+		}
+	}
+
+	s.states[state] = now.Add(s.ttl)
+	return nil
+}
+
+// Consume implements StateStore
+func (s *MemoryStateStore) Consume(ctx context.Context, state string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exp, ok := s.states[state]
+	delete(s.states, state)
+
+	return ok && time.Now().Before(exp), nil
+}
+
+// OAuthStateCookie is the cookie Start binds the state of a flow to the browser with.
+// The callback only accepts a state that matches the cookie so a flow started in one browser
+// can't be completed in another.
+const OAuthStateCookie = "lazada_oauth_state"
+
+// CallbackHandler completes the OAuth flow started with Start.
+// Serve it at the redirect URL registered for the app, it validates the state,
+// exchanges the code for a token and passes the token to OnToken.
+type CallbackHandler struct {
+	// The auth service used to exchange the code
+	Auth *AuthService
+
+	// Where issued states are kept, required
+	States StateStore
+
+	// The URL the handler is served at, the seller is sent back here after authorizing
+	RedirectURL string
+
+	// Called with the token once the flow completed, it is responsible for writing the response
+	OnToken func(w http.ResponseWriter, r *http.Request, t *Token)
+
+	// Called when the flow fails, it is responsible for writing the response.
+	// If nil a plain text error is written.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// NewCallbackHandler returns a handler checking that the fields it needs are set
+func NewCallbackHandler(auth *AuthService, states StateStore, redirectURL string,
+	onToken func(w http.ResponseWriter, r *http.Request, t *Token)) (*CallbackHandler, error) {
+	h := &CallbackHandler{Auth: auth, States: states, RedirectURL: redirectURL, OnToken: onToken}
+	if err := h.validate(); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *CallbackHandler) validate() error {
+	switch {
+	case h.Auth == nil:
+		return errors.New("callback handler needs an auth service")
+	case h.States == nil:
+		return errors.New("callback handler needs a state store")
+	case h.OnToken == nil:
+		return errors.New("callback handler needs an OnToken func")
+	}
+
+	return nil
+}
+
+// AuthURL issues a new state and returns it with the URL to send the seller to.
+// Start should be used instead unless the state is bound to the browser in the OAuthStateCookie some other way.
+func (h *CallbackHandler) AuthURL(ctx context.Context) (authURL, state string, err error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", errors.Wrap(err, "cant generate state")
+	}
+
+	state = hex.EncodeToString(b)
+	if err := h.States.Save(ctx, state); err != nil {
+		return "", "", errors.Wrap(err, "cant save state")
+	}
+
+	return h.Auth.AuthURL(h.RedirectURL, state), state, nil
+}
+
+// Start redirects the seller to the open platform to begin the flow
+// and binds the state to the browser with the OAuthStateCookie.
+// It can be used as a http.HandlerFunc.
+func (h *CallbackHandler) Start(w http.ResponseWriter, r *http.Request) {
+	if err := h.validate(); err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	u, state, err := h.AuthURL(r.Context())
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, h.stateCookie(state, 0))
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+// stateCookie returns the cookie holding state, a negative maxAge deletes it
+func (h *CallbackHandler) stateCookie(state string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     OAuthStateCookie,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.RedirectURL, "https://"),
+
+		// Lax so the cookie is sent on the redirect back from the open platform
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// ServeHTTP handles the redirect back from the open platform
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.validate(); err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	// The state has to come from the browser that started the flow, not just any flow
+	cookie, err := r.Cookie(OAuthStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(q.Get("state"))) != 1 {
+		h.fail(w, r, ErrInvalidState)
+		return
+	}
+	http.SetCookie(w, h.stateCookie("", -1))
+
+	ok, err := h.States.Consume(ctx, q.Get("state"))
+	if err != nil {
+		h.fail(w, r, errors.Wrap(err, "cant check state"))
+		return
+	}
+
+	if !ok {
+		h.fail(w, r, ErrInvalidState)
+		return
+	}
+
+	if code := q.Get("error"); code != "" {
+		h.fail(w, r, &OAuthError{Code: code, Description: q.Get("error_description")})
+		return
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		h.fail(w, r, &OAuthError{Code: "missing_code", Description: "no code in the callback"})
+		return
+	}
+
+	t, err := h.Auth.Exchange(ctx, code)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+
+	h.OnToken(w, r, t)
+}
+
+func (h *CallbackHandler) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if h.OnError != nil {
+		h.OnError(w, r, err)
+		return
+	}
+
+	switch err.(type) {
+	case *OAuthError:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case *ErrorResponse:
+		http.Error(w, "cant exchange code", http.StatusBadGateway)
+	default:
+		if err == ErrInvalidState {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,109 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/auth/token/create", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "thecode", r.URL.Query().Get("code"))
+		fmt.Fprint(w, `{"code":"0","access_token":"access","refresh_token":"refresh","expires_in":3600,"account_id":"100","country":"sg"}`)
+	})
+
+	var got *Token
+	h, err := NewCallbackHandler(client.Auth, NewMemoryStateStore(time.Minute), "https://example.com/callback",
+		func(w http.ResponseWriter, r *http.Request, tok *Token) {
+			got = tok
+			w.WriteHeader(http.StatusNoContent)
+		})
+	require.NoError(t, err)
+
+	// Starting the flow redirects to the platform with a new state bound to the browser
+	rec := httptest.NewRecorder()
+	h.Start(rec, httptest.NewRequest("GET", "/start", nil))
+	require.Equal(t, http.StatusFound, rec.Code)
+
+	authURL, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	state := authURL.Query().Get("state")
+	assert.Equal(t, "https://example.com/callback", authURL.Query().Get("redirect_uri"))
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	cookie := cookies[0]
+	assert.Equal(t, OAuthStateCookie, cookie.Name)
+	assert.Equal(t, state, cookie.Value)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+
+	callback := func(query string, withCookie bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/callback?"+query, nil)
+		if withCookie {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// An unknown state is rejected
+	assert.Equal(t, http.StatusBadRequest, callback("code=thecode&state=forged", true).Code)
+
+	// A valid state is rejected in a browser that didn't start the flow
+	assert.Equal(t, http.StatusBadRequest, callback("code=thecode&state="+state, false).Code)
+	assert.Nil(t, got)
+
+	rec = callback("code=thecode&state="+state, true)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, got)
+	assert.Equal(t, "access", got.AccessToken)
+
+	// The cookie is cleared once used
+	require.Len(t, rec.Result().Cookies(), 1)
+	assert.True(t, rec.Result().Cookies()[0].MaxAge < 0)
+
+	// A state can only be used once
+	assert.Equal(t, http.StatusBadRequest, callback("code=thecode&state="+state, true).Code)
+}
+
+func TestCallbackHandler_PlatformError(t *testing.T) {
+	states := NewMemoryStateStore(time.Minute)
+	states.Save(context.Background(), "state")
+
+	var got error
+	h := &CallbackHandler{
+		Auth:    &AuthService{},
+		States:  states,
+		OnToken: func(w http.ResponseWriter, r *http.Request, t *Token) {},
+		OnError: func(w http.ResponseWriter, r *http.Request, err error) { got = err },
+	}
+
+	req := httptest.NewRequest("GET", "/callback?error=access_denied&error_description=declined&state=state", nil)
+	req.AddCookie(&http.Cookie{Name: OAuthStateCookie, Value: "state"})
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	require.IsType(t, &OAuthError{}, got)
+	assert.Equal(t, "access_denied", got.(*OAuthError).Code)
+}
+
+func TestCallbackHandler_Incomplete(t *testing.T) {
+	_, err := NewCallbackHandler(&AuthService{}, NewMemoryStateStore(time.Minute), "https://example.com/callback", nil)
+	assert.Error(t, err)
+
+	// A handler built without a constructor fails instead of panicking
+	h := &CallbackHandler{Auth: &AuthService{}, States: NewMemoryStateStore(time.Minute)}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/callback?code=thecode&state=state", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,212 @@
+package lazada
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// The Order Service deals with any methods under the "Order" category of the open platform
+// All of the order API calls require a client access token
+type OrderService service
+
+// OrderSearchOptions are used to filter the orders returned by GetOrders
+type OrderSearchOptions struct {
+	// Only return orders created after this time
+	CreatedAfter *time.Time `url:"created_after,omitempty"`
+
+	// Only return orders created before this time
+	CreatedBefore *time.Time `url:"created_before,omitempty"`
+
+	// Only return orders updated after this time
+	UpdatedAfter *time.Time `url:"update_after,omitempty"`
+
+	// Only return orders updated before this time
+	UpdatedBefore *time.Time `url:"update_before,omitempty"`
+
+	// Filter the order status, e.g. "pending", "ready_to_ship" or "delivered"
+	Status *string `url:"status,omitempty"`
+
+	// Sort by "created_at" or "updated_at"
+	SortBy *string `url:"sort_by,omitempty"`
+
+	// Sort direction "ASC" or "DESC"
+	SortDirection *string `url:"sort_direction,omitempty"`
+
+	// Offset the results by
+	Offset int `url:"offset"`
+
+	// Limit the amount of returned results
+	Limit int `url:"limit"`
+}
+
+type Address struct {
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Phone         string `json:"phone"`
+	Phone2        string `json:"phone2"`
+	Address1      string `json:"address1"`
+	Address2      string `json:"address2"`
+	Address3      string `json:"address3"`
+	Address4      string `json:"address4"`
+	Address5      string `json:"address5"`
+	City          string `json:"city"`
+	PostCode      string `json:"post_code"`
+	Country       string `json:"country"`
+	CustomerEmail string `json:"customer_email"`
+}
+
+type Order struct {
+	OrderID                    int64           `json:"order_id"`
+	OrderNumber                int64           `json:"order_number"`
+	CustomerFirstName          string          `json:"customer_first_name"`
+	CustomerLastName           string          `json:"customer_last_name"`
+	PaymentMethod              string          `json:"payment_method"`
+	Remarks                    string          `json:"remarks"`
+	DeliveryInfo               string          `json:"delivery_info"`
+	Price                      decimal.Decimal `json:"price"`
+	ShippingFee                decimal.Decimal `json:"shipping_fee"`
+	Voucher                    decimal.Decimal `json:"voucher"`
+	VoucherCode                string          `json:"voucher_code"`
+	GiftOption                 bool            `json:"gift_option"`
+	GiftMessage                string          `json:"gift_message"`
+	NationalRegistrationNumber string          `json:"national_registration_number"`
+	ItemsCount                 int             `json:"items_count"`
+	PromisedShippingTimes      string          `json:"promised_shipping_times"`
+	ExtraAttributes            string          `json:"extra_attributes"`
+	Statuses                   []string        `json:"statuses"`
+	AddressBilling             *Address        `json:"address_billing"`
+	AddressShipping            *Address        `json:"address_shipping"`
+	CreatedAt                  string          `json:"created_at"`
+	UpdatedAt                  string          `json:"updated_at"`
+}
+
+type GetOrdersResponse struct {
+	Count      int      `json:"count"`
+	CountTotal int      `json:"countTotal"`
+	Orders     []*Order `json:"orders"`
+}
+
+type OrderItem struct {
+	OrderItemID          int64           `json:"order_item_id"`
+	OrderID              int64           `json:"order_id"`
+	ShopID               string          `json:"shop_id"`
+	Name                 string          `json:"name"`
+	SKU                  string          `json:"sku"`
+	ShopSKU              string          `json:"shop_sku"`
+	Variation            string          `json:"variation"`
+	ShippingType         string          `json:"shipping_type"`
+	ItemPrice            decimal.Decimal `json:"item_price"`
+	PaidPrice            decimal.Decimal `json:"paid_price"`
+	Currency             string          `json:"currency"`
+	WalletCredits        decimal.Decimal `json:"wallet_credits"`
+	TaxAmount            decimal.Decimal `json:"tax_amount"`
+	ShippingAmount       decimal.Decimal `json:"shipping_amount"`
+	ShippingServiceCost  decimal.Decimal `json:"shipping_service_cost"`
+	VoucherAmount        decimal.Decimal `json:"voucher_amount"`
+	VoucherCode          string          `json:"voucher_code"`
+	Status               string          `json:"status"`
+	ShipmentProvider     string          `json:"shipment_provider"`
+	ShippingProviderType string          `json:"shipping_provider_type"`
+	TrackingCode         string          `json:"tracking_code"`
+	TrackingCodePre      string          `json:"tracking_code_pre"`
+	PackageID            string          `json:"package_id"`
+	IsDigital            int             `json:"is_digital"`
+	DigitalDeliveryInfo  string          `json:"digital_delivery_info"`
+	Reason               string          `json:"reason"`
+	ReasonDetail         string          `json:"reason_detail"`
+	PurchaseOrderID      string          `json:"purchase_order_id"`
+	PurchaseOrderNumber  string          `json:"purchase_order_number"`
+	ReturnStatus         string          `json:"return_status"`
+	ProductMainImage     string          `json:"product_main_image"`
+	ProductDetailURL     string          `json:"product_detail_url"`
+	InvoiceNumber        string          `json:"invoice_number"`
+	ExtraAttributes      string          `json:"extra_attributes"`
+	CreatedAt            string          `json:"created_at"`
+	UpdatedAt            string          `json:"updated_at"`
+}
+
+// MultipleOrderItems is the list of items belonging to a single order as returned by GetMultipleOrderItems
+type MultipleOrderItems struct {
+	OrderID     int64        `json:"order_id"`
+	OrderNumber int64        `json:"order_number"`
+	OrderItems  []*OrderItem `json:"order_items"`
+}
+
+// GetOrders returns the orders matching the search options
+// If opts is nil then the default list options are used
+// Requires a client access token
+func (o *OrderService) GetOrders(ctx context.Context, opts *OrderSearchOptions) (*GetOrdersResponse, error) {
+	search := OrderSearchOptions{
+		Offset: DefaultListOptions.Offset,
+		Limit:  DefaultListOptions.Limit,
+	}
+	if opts != nil {
+		// Copy so the defaults below don't change the options of the caller
+		search = *opts
+	}
+
+	// The open platform requires one of the two time filters to be set
+	if search.CreatedAfter == nil && search.UpdatedAfter == nil {
+		epoch := time.Unix(0, 0)
+		search.CreatedAfter = &epoch
+	}
+
+	resp := &GetOrdersResponse{}
+	_, err := o.client.CallAPI(ctx, "GetOrders", &search, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetOrder returns a single order by its id
+// Requires a client access token
+func (o *OrderService) GetOrder(ctx context.Context, id int64) (*Order, error) {
+	order := &Order{}
+	_, err := o.client.CallAPI(ctx, "GetOrder", url.Values{"order_id": {strconv.FormatInt(id, 10)}}, nil, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetOrderItems returns the items of a single order
+// Requires a client access token
+func (o *OrderService) GetOrderItems(ctx context.Context, id int64) ([]*OrderItem, error) {
+	items := []*OrderItem{}
+	_, err := o.client.CallAPI(ctx, "GetOrderItems", url.Values{"order_id": {strconv.FormatInt(id, 10)}}, nil, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetMultipleOrderItems returns the items of all the orders provided in a single call
+// Requires a client access token
+func (o *OrderService) GetMultipleOrderItems(ctx context.Context, ids []int64) ([]*MultipleOrderItems, error) {
+	items := []*MultipleOrderItems{}
+	_, err := o.client.CallAPI(ctx, "GetMultipleOrderItems", url.Values{"order_ids": {sliceInt64(ids)}}, nil, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// sliceInt64 takes in a slice of ids and returns a string used in query parameters for the open platform
+func sliceInt64(in []int64) string {
+	ids := make([]string, len(in))
+	for i, id := range in {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+
+	return "[" + strings.Join(ids, ",") + "]"
+}
@@ -0,0 +1,176 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderService_GetOrders(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/orders/get", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "2018-10-01T00:00:00Z", q.Get("created_after"))
+		assert.Equal(t, "pending", q.Get("status"))
+		assert.Equal(t, "faketoken", q.Get("access_token"))
+		fmt.Fprint(w, `{"code":"0","data":{"count":1,"countTotal":1,"orders":[{"order_id":1234,"price":"19.90","statuses":["pending"]}]}}`)
+	})
+
+	after := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	status := "pending"
+	resp, err := client.Orders.GetOrders(context.Background(), &OrderSearchOptions{CreatedAfter: &after, Status: &status, Limit: 10})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Orders, 1)
+	assert.Equal(t, int64(1234), resp.Orders[0].OrderID)
+	assert.True(t, decimal.RequireFromString("19.90").Equal(resp.Orders[0].Price))
+}
+
+func TestOrderService_GetOrders_Defaults(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/orders/get", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "1970-01-01T00:00:00Z", q.Get("created_after"))
+		assert.Equal(t, "100", q.Get("limit"))
+		fmt.Fprint(w, `{"code":"0","data":{"count":0,"countTotal":0,"orders":[]}}`)
+	})
+
+	_, err := client.Orders.GetOrders(context.Background(), nil)
+	require.NoError(t, err)
+
+	// The default time filter is not written back to the options of the caller
+	opts := &OrderSearchOptions{Limit: 100}
+	_, err = client.Orders.GetOrders(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Nil(t, opts.CreatedAfter)
+}
+
+func TestOrderService_GetMultipleOrderItems(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/orders/items/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "[1,2]", r.URL.Query().Get("order_ids"))
+		fmt.Fprint(w, `{"code":"0","data":[{"order_id":1,"order_items":[{"order_item_id":10,"paid_price":5}]},{"order_id":2,"order_items":[]}]}`)
+	})
+
+	items, err := client.Orders.GetMultipleOrderItems(context.Background(), []int64{1, 2})
+	require.NoError(t, err)
+
+	require.Len(t, items, 2)
+	require.Len(t, items[0].OrderItems, 1)
+	assert.True(t, decimal.New(5, 0).Equal(items[0].OrderItems[0].PaidPrice))
+}
+
+func TestOrderService_RequiresToken(t *testing.T) {
+	c, err := NewClient("123456", "testsecretnotarealsecret", Singapore)
+	require.NoError(t, err)
+	_, err = c.Orders.GetOrder(context.Background(), 1)
+	assert.Error(t, err)
+}
+
+func TestOrderService_Pack(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/order/pack", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		q := r.URL.Query()
+		assert.Equal(t, "[10,11]", q.Get("order_item_ids"))
+		assert.Equal(t, "LEX", q.Get("shipping_provider"))
+		assert.Equal(t, DeliveryDropship, q.Get("delivery_type"))
+		fmt.Fprint(w, `{"code":"0","data":{"order_items":[{"order_item_id":10,"tracking_number":"TN1"},{"order_item_id":11,"tracking_number":"TN1"}]}}`)
+	})
+
+	opts := &PackOptions{OrderItemIDs: []int64{10, 11}, ShippingProvider: "LEX"}
+	items, err := client.Orders.Pack(context.Background(), opts)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "TN1", items[0].TrackingNumber)
+	assert.Empty(t, opts.DeliveryType)
+}
+
+func TestOrderService_ReadyToShip(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/order/rts", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		q := r.URL.Query()
+		assert.Equal(t, "[10]", q.Get("order_item_ids"))
+		assert.Equal(t, "LEX", q.Get("shipment_provider"))
+		assert.Equal(t, "TN1", q.Get("tracking_number"))
+		assert.Equal(t, DeliveryDropship, q.Get("delivery_type"))
+		fmt.Fprint(w, `{"code":"0","data":{"order_items":[{"order_item_id":10,"tracking_number":"TN1"}]}}`)
+	})
+
+	opts := &ReadyToShipOptions{OrderItemIDs: []int64{10}, ShipmentProvider: "LEX", TrackingNumber: "TN1"}
+	items, err := client.Orders.ReadyToShip(context.Background(), opts)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, int64(10), items[0].OrderItemID)
+
+	// The default delivery type is not written back to the options of the caller
+	assert.Empty(t, opts.DeliveryType)
+}
+
+func TestOrderService_NilOptions(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	for _, path := range []string{"/rest/order/pack", "/rest/order/rts"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, DeliveryDropship, r.URL.Query().Get("delivery_type"))
+			fmt.Fprint(w, `{"code":"InvalidParameter","type":"ISV","message":"order_item_ids is required"}`)
+		})
+	}
+
+	ctx := context.Background()
+	_, err := client.Orders.Pack(ctx, nil)
+	assert.Error(t, err)
+
+	_, err = client.Orders.ReadyToShip(ctx, nil)
+	assert.Error(t, err)
+}
+
+func TestOrderService_Cancel(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/order/cancel", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		q := r.URL.Query()
+		assert.Equal(t, "10", q.Get("order_item_id"))
+		assert.Equal(t, "15", q.Get("reason_id"))
+		assert.Equal(t, "out of stock", q.Get("reason_detail"))
+		fmt.Fprint(w, `{"code":"0"}`)
+	})
+
+	require.NoError(t, client.Orders.Cancel(context.Background(), 10, 15, "out of stock"))
+}
+
+func TestOrderService_Document(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/order/document/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "invoice", r.URL.Query().Get("doc_type"))
+		// base64 of "<html></html>"
+		fmt.Fprint(w, `{"code":"0","data":{"document":{"document_type":"invoice","mime_type":"text/html","file":"PGh0bWw+PC9odG1sPg=="}}}`)
+	})
+
+	doc, err := client.Orders.Document(context.Background(), DocumentInvoice, []int64{10})
+	require.NoError(t, err)
+	assert.Equal(t, "text/html", doc.MimeType)
+	assert.Equal(t, "<html></html>", string(doc.File))
+}
@@ -0,0 +1,171 @@
+package lazada
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxPriceQuantitySKUs is the most skus the open platform accepts in one price and quantity update
+const MaxPriceQuantitySKUs = 50
+
+// PriceQuantity is a price and stock update for a single sku
+// Fields left nil are not changed
+type PriceQuantity struct {
+	SellerSKU string
+
+	Quantity *int
+
+	Price     *decimal.Decimal
+	SalePrice *decimal.Decimal
+
+	// The sale price is only used between these dates
+	SaleStart *time.Time
+	SaleEnd   *time.Time
+}
+
+type priceQuantityRequest struct {
+	XMLName xml.Name            `xml:"Request"`
+	Skus    []*priceQuantitySku `xml:"Product>Skus>Sku"`
+}
+
+type priceQuantitySku struct {
+	SellerSku     string `xml:"SellerSku"`
+	Quantity      *int   `xml:"Quantity,omitempty"`
+	Price         string `xml:"Price,omitempty"`
+	SalePrice     string `xml:"SalePrice,omitempty"`
+	SaleStartDate string `xml:"SaleStartDate,omitempty"`
+	SaleEndDate   string `xml:"SaleEndDate,omitempty"`
+}
+
+// saleDateFormat is the format of the sale window dates
+const saleDateFormat = "2006-01-02"
+
+func newPriceQuantitySku(u *PriceQuantity) *priceQuantitySku {
+	sku := &priceQuantitySku{SellerSku: u.SellerSKU, Quantity: u.Quantity}
+	if u.Price != nil {
+		sku.Price = u.Price.StringFixed(2)
+	}
+	if u.SalePrice != nil {
+		sku.SalePrice = u.SalePrice.StringFixed(2)
+	}
+	if u.SaleStart != nil {
+		sku.SaleStartDate = u.SaleStart.Format(saleDateFormat)
+	}
+	if u.SaleEnd != nil {
+		sku.SaleEndDate = u.SaleEnd.Format(saleDateFormat)
+	}
+
+	return sku
+}
+
+// PriceQuantityResult reports which skus of an UpdatePriceQuantity call were updated
+type PriceQuantityResult struct {
+	// The seller skus that were updated
+	Updated []string
+
+	// The seller skus that were not updated and why
+	// The errors are usually an *ErrorResponse with only the details for that sku
+	Failed map[string]error
+}
+
+// Err returns an error listing the failed skus or nil if every sku was updated
+func (r *PriceQuantityResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+
+	skus := make([]string, 0, len(r.Failed))
+	for sku := range r.Failed {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	return fmt.Errorf("%d skus were not updated: %s", len(skus), strings.Join(skus, ", "))
+}
+
+// UpdatePriceQuantity updates the price and stock of skus without sending the whole product.
+// The updates are sent in batches of MaxPriceQuantitySKUs, a failed batch does not stop the following ones.
+// The result reports which skus were updated, an error is only returned if the updates could not be attempted
+// or ctx is done, in which case the skus that were not sent are reported as failed with the error of ctx.
+// Requires a client access token
+func (p *ProductService) UpdatePriceQuantity(ctx context.Context, updates []*PriceQuantity) (*PriceQuantityResult, error) {
+	if !p.client.hasToken() {
+		return nil, ErrTokenRequired
+	}
+
+	for _, u := range updates {
+		if u.SellerSKU == "" {
+			return nil, errors.New("every update needs a seller sku")
+		}
+	}
+
+	result := &PriceQuantityResult{Failed: make(map[string]error)}
+	for start := 0; start < len(updates); start += MaxPriceQuantitySKUs {
+		if err := ctx.Err(); err != nil {
+			for _, u := range updates[start:] {
+				result.Failed[u.SellerSKU] = err
+			}
+			return result, err
+		}
+
+		end := start + MaxPriceQuantitySKUs
+		if end > len(updates) {
+			end = len(updates)
+		}
+
+		p.updatePriceQuantityBatch(ctx, updates[start:end], result)
+	}
+
+	return result, nil
+}
+
+// updatePriceQuantityBatch sends a single batch and adds the outcome of every sku to result
+func (p *ProductService) updatePriceQuantityBatch(ctx context.Context, batch []*PriceQuantity, result *PriceQuantityResult) {
+	req := &priceQuantityRequest{}
+	for _, u := range batch {
+		req.Skus = append(req.Skus, newPriceQuantitySku(u))
+	}
+
+	_, err := p.client.CallAPI(ctx, "UpdatePriceQuantity", nil, req, nil)
+	if err == nil {
+		for _, u := range batch {
+			result.Updated = append(result.Updated, u.SellerSKU)
+		}
+		return
+	}
+
+	// When some skus are rejected the others in the batch are still updated and the details name the rejected ones,
+	// without details about single skus the whole batch failed
+	errResp, ok := err.(*ErrorResponse)
+	bySKU := map[string][]*ErrorDetails{}
+	if ok {
+		bySKU = errResp.BySKU()
+		delete(bySKU, "")
+	}
+
+	if len(bySKU) == 0 {
+		for _, u := range batch {
+			result.Failed[u.SellerSKU] = err
+		}
+		return
+	}
+
+	for _, u := range batch {
+		detail, failed := bySKU[u.SellerSKU]
+		if !failed {
+			result.Updated = append(result.Updated, u.SellerSKU)
+			continue
+		}
+
+		skuErr := *errResp
+		skuErr.Detail = detail
+		result.Failed[u.SellerSKU] = &skuErr
+	}
+}
@@ -0,0 +1,89 @@
+package lazada
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductService_UpdatePriceQuantity(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var batches []int
+	mux.HandleFunc("/rest/product/price_quantity/update", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		req := &priceQuantityRequest{}
+		require.NoError(t, xml.Unmarshal([]byte(r.PostForm.Get("payload")), req))
+		batches = append(batches, len(req.Skus))
+
+		if len(batches) == 1 {
+			first := req.Skus[0]
+			assert.Equal(t, "23.50", first.Price)
+			assert.Equal(t, "20.00", first.SalePrice)
+			assert.Equal(t, "2024-01-02", first.SaleStartDate)
+			require.NotNil(t, first.Quantity)
+			assert.Equal(t, 0, *first.Quantity)
+			assert.Nil(t, req.Skus[1].Quantity)
+
+			fmt.Fprint(w, `{"code":"0","data":{}}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"code":"208","type":"ISV","message":"some skus failed","detail":[
+			{"field":"SellerSku","message":"not found","seller_sku":"sku-51"}]}`)
+	})
+
+	zero := 0
+	price := decimal.RequireFromString("23.5")
+	sale := decimal.New(20, 0)
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	updates := []*PriceQuantity{{SellerSKU: "sku-0", Quantity: &zero, Price: &price, SalePrice: &sale, SaleStart: &start}}
+	for i := 1; i < 55; i++ {
+		updates = append(updates, &PriceQuantity{SellerSKU: fmt.Sprintf("sku-%d", i), Price: &price})
+	}
+
+	result, err := client.Products.UpdatePriceQuantity(context.Background(), updates)
+	require.NoError(t, err)
+	assert.Equal(t, []int{MaxPriceQuantitySKUs, 5}, batches)
+	assert.Len(t, result.Updated, 54)
+	require.Len(t, result.Failed, 1)
+	assert.True(t, errors.Is(result.Failed["sku-51"], ErrSKUNotFound))
+	assert.Len(t, result.Failed["sku-51"].(*ErrorResponse).Detail, 1)
+	assert.EqualError(t, result.Err(), "1 skus were not updated: sku-51")
+}
+
+func TestProductService_UpdatePriceQuantityBatchError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/product/price_quantity/update", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":"InvalidParameter","type":"ISV","message":"bad payload"}`)
+	})
+
+	result, err := client.Products.UpdatePriceQuantity(context.Background(),
+		[]*PriceQuantity{{SellerSKU: "a"}, {SellerSKU: "b"}})
+	require.NoError(t, err)
+	assert.Empty(t, result.Updated)
+	assert.True(t, errors.Is(result.Failed["a"], ErrInvalidParameter))
+	assert.True(t, errors.Is(result.Failed["b"], ErrInvalidParameter))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = client.Products.UpdatePriceQuantity(ctx, []*PriceQuantity{{SellerSKU: "a"}})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, context.Canceled, result.Failed["a"])
+
+	_, err = client.Products.UpdatePriceQuantity(context.Background(), []*PriceQuantity{{}})
+	assert.Error(t, err)
+}
@@ -3,8 +3,8 @@ package lazada
 import (
 	"context"
 	"encoding/xml"
-	"errors"
-	"fmt"
+	"net/url"
+	"strconv"
 
 	"github.com/shopspring/decimal"
 )
@@ -26,18 +26,8 @@ func (p *ProductService) Brands(ctx context.Context, opts *ListOptions) ([]*Bran
 		opts = &DefaultListOptions
 	}
 
-	u, err := addOptions(apiNames["GetBrands"], opts)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := p.client.NewRequest("GET", u, nil)
-	if err != nil {
-		return nil, err
-	}
-
 	brands := []*Brand{}
-	_, err = p.client.Do(ctx, req, &brands)
+	_, err := p.client.CallAPI(ctx, "GetBrands", opts, nil, &brands)
 	if err != nil {
 		return nil, err
 	}
@@ -55,13 +45,8 @@ type CategoryTree struct {
 
 // CategoryTree returns all the categories available in the region set
 func (p *ProductService) CategoryTree(ctx context.Context) ([]*CategoryTree, error) {
-	req, err := p.client.NewRequest("GET", apiNames["CategoryTree"], nil)
-	if err != nil {
-		return nil, err
-	}
-
 	tree := []*CategoryTree{}
-	_, err = p.client.Do(ctx, req, &tree)
+	_, err := p.client.CallAPI(ctx, "CategoryTree", nil, nil, &tree)
 	if err != nil {
 		return nil, err
 	}
@@ -84,17 +69,8 @@ type ImageResponse struct {
 // MigrateImage lets you move any publicly accessible image into the Lazada platform
 // Requires a client access token
 func (p *ProductService) MigrateImage(ctx context.Context, imgURL string) (*ImageResponse, error) {
-	if p.client.accessToken == "" {
-		return nil, errors.New("an access token is required for this api call")
-	}
-
-	req, err := p.client.NewRequest("POST", apiNames["ImageMigrate"], ImageReq{URL: imgURL})
-	if err != nil {
-		return nil, err
-	}
-
 	img := &ImageResponse{}
-	_, err = p.client.Do(ctx, req, img)
+	_, err := p.client.CallAPI(ctx, "ImageMigrate", nil, ImageReq{URL: imgURL}, img)
 	if err != nil {
 		return nil, err
 	}
@@ -118,13 +94,10 @@ type CategoryOptions struct {
 
 // CategoryAttributes returns all the attributes related to the category id provided
 func (p *ProductService) CategoryAttributes(ctx context.Context, id int) ([]*CategoryAttributes, error) {
-	req, err := p.client.NewRequest("GET", fmt.Sprintf("%s?primary_category_id=%d", apiNames["CategoryAttributes"], id), nil)
-	if err != nil {
-		return nil, err
-	}
+	params := url.Values{"primary_category_id": {strconv.Itoa(id)}}
 
 	attr := []*CategoryAttributes{}
-	_, err = p.client.Do(ctx, req, &attr)
+	_, err := p.client.CallAPI(ctx, "CategoryAttributes", params, nil, &attr)
 	if err != nil {
 		return nil, err
 	}
@@ -149,17 +122,8 @@ type SKUItem struct {
 //
 // Requires a client access token
 func (p *ProductService) Create(ctx context.Context, pReq *Product) (*CreateProductResponse, error) {
-	if p.client.accessToken == "" {
-		return nil, errors.New("an access token is required for this api call")
-	}
-
-	req, err := p.client.NewRequest("POST", apiNames["CreateProduct"], &ProductRequest{Product: pReq})
-	if err != nil {
-		return nil, err
-	}
-
 	resp := &CreateProductResponse{}
-	_, err = p.client.Do(ctx, req, resp)
+	_, err := p.client.CallAPI(ctx, "CreateProduct", nil, &ProductRequest{Product: pReq}, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -170,16 +134,7 @@ func (p *ProductService) Create(ctx context.Context, pReq *Product) (*CreateProd
 // Update lets you update an existing product on the open platform
 // Requires a client access token
 func (p *ProductService) Update(ctx context.Context, pReq *Product) error {
-	if p.client.accessToken == "" {
-		return errors.New("an access token is required for this api call")
-	}
-
-	req, err := p.client.NewRequest("POST", apiNames["UpdateProduct"], &ProductRequest{Product: pReq})
-	if err != nil {
-		return err
-	}
-
-	_, err = p.client.Do(ctx, req, nil)
+	_, err := p.client.CallAPI(ctx, "UpdateProduct", nil, &ProductRequest{Product: pReq}, nil)
 	if err != nil {
 		return err
 	}
@@ -253,18 +208,8 @@ func (p *ProductService) Get(ctx context.Context, opts *SearchOptions) (*GetProd
 		opts.Filter = "live"
 	}
 
-	u, err := addOptions(apiNames["GetProducts"], opts)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := p.client.NewRequest("GET", u, nil)
-	if err != nil {
-		return nil, err
-	}
-
 	resp := &GetProductResponse{}
-	_, err = p.client.Do(ctx, req, resp)
+	_, err := p.client.CallAPI(ctx, "GetProducts", opts, nil, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,198 @@
+package lazada
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitKey identifies which quotas a call counts towards
+type RateLimitKey struct {
+	AppKey      string
+	AccessToken string
+
+	// The name of the API in apiNames, e.g. "GetProducts"
+	API string
+}
+
+// RateLimiter is called before every request is sent, including retries.
+// Wait should block until the call is allowed or return an error if ctx is done first.
+type RateLimiter interface {
+	Wait(ctx context.Context, key RateLimitKey) error
+}
+
+// Limit is the number of calls per second allowed with bursts of up to Burst calls.
+// A zero Rate means unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// TokenBucketLimiter is the RateLimiter provided with the library.
+// It keeps a token bucket for the app key, one for every access token, and optionally one per API name.
+//
+// The limiter is shared by every client created from the one it is set on with NewTokenClient,
+// so a single limiter can be used to stay within the quotas across many sellers.
+// Buckets that have been idle long enough to refill are dropped so tokens that are no longer used don't keep memory.
+type TokenBucketLimiter struct {
+	app    Limit
+	seller Limit
+
+	// now is replaced in tests
+	now func() time.Time
+
+	mu        sync.Mutex
+	apis      map[string]Limit
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucketSweepInterval is how often idle buckets are looked for
+const bucketSweepInterval = time.Minute
+
+// NewTokenBucketLimiter returns a limiter enforcing app across all calls of an app key
+// and seller across all calls of a single access token.
+func NewTokenBucketLimiter(app, seller Limit) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		app:     app,
+		seller:  seller,
+		now:     time.Now,
+		apis:    make(map[string]Limit),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// SetAPILimit adds a limit for a single API, e.g. "CreateProduct".
+// The limit applies per access token, or per app key for calls made without one.
+func (l *TokenBucketLimiter) SetAPILimit(api string, lim Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.apis[api] = lim
+}
+
+// Wait implements RateLimiter
+func (l *TokenBucketLimiter) Wait(ctx context.Context, key RateLimitKey) error {
+	delay, reserved := l.reserve(key)
+	if delay <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		// Give the tokens back since the call will never be made
+		l.mu.Lock()
+		for _, b := range reserved {
+			b.tokens++
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from every bucket the call counts towards and returns how long to wait for the last one
+func (l *TokenBucketLimiter) reserve(key RateLimitKey) (time.Duration, []*bucket) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Read under the lock so buckets are never refilled with a time older than their last update
+	now := l.now()
+	if now.Sub(l.lastSweep) >= bucketSweepInterval {
+		l.sweep(now)
+	}
+
+	reserved := []*bucket{}
+	var delay time.Duration
+
+	reserve := func(id string, lim Limit) {
+		if lim.Rate <= 0 {
+			return
+		}
+
+		b, ok := l.buckets[id]
+		if !ok {
+			b = newBucket(lim, now)
+			l.buckets[id] = b
+		} else if lim = normalizeLimit(lim); b.limit != lim {
+			// The limit of an api was changed after its bucket was created
+			b.refill(now)
+			b.limit = lim
+			if max := float64(lim.Burst); b.tokens > max {
+				b.tokens = max
+			}
+		}
+
+		if d := b.reserve(now); d > delay {
+			delay = d
+		}
+		reserved = append(reserved, b)
+	}
+
+	reserve("app:"+key.AppKey, l.app)
+	if key.AccessToken != "" {
+		reserve("seller:"+key.AppKey+":"+key.AccessToken, l.seller)
+	}
+	if lim, ok := l.apis[key.API]; ok {
+		reserve("api:"+key.AppKey+":"+key.AccessToken+":"+key.API, lim)
+	}
+
+	return delay, reserved
+}
+
+// sweep drops the buckets that are full again, a new bucket starts full so dropping them changes nothing
+func (l *TokenBucketLimiter) sweep(now time.Time) {
+	for id, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(l.buckets, id)
+		}
+	}
+
+	l.lastSweep = now
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(lim Limit, now time.Time) *bucket {
+	lim = normalizeLimit(lim)
+	return &bucket{limit: lim, tokens: float64(lim.Burst), last: now}
+}
+
+// normalizeLimit makes sure a limit allows at least one call at a time
+func normalizeLimit(lim Limit) Limit {
+	if lim.Burst < 1 {
+		lim.Burst = 1
+	}
+
+	return lim
+}
+
+// refill adds the tokens earned since the last update
+func (b *bucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+	if max := float64(b.limit.Burst); b.tokens > max {
+		b.tokens = max
+	}
+	b.last = now
+}
+
+// reserve takes a token from the bucket and returns how long to wait until it is available.
+// The bucket can go negative so later callers queue up behind earlier ones.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.refill(now)
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+}
@@ -0,0 +1,116 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucketLimiter(t *testing.T) {
+	l := NewTokenBucketLimiter(Limit{}, Limit{Rate: 1, Burst: 1})
+	key := RateLimitKey{AppKey: "app", AccessToken: "seller1", API: "GetProducts"}
+
+	assert.NoError(t, l.Wait(context.Background(), key))
+
+	// The second call for the same seller has to wait for a new token
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.Wait(ctx, key))
+
+	// Other sellers have their own bucket
+	key.AccessToken = "seller2"
+	assert.NoError(t, l.Wait(context.Background(), key))
+
+	// API limits are applied on top of the seller limit
+	l.SetAPILimit("CreateProduct", Limit{Rate: 1, Burst: 1})
+	key.AccessToken = "seller3"
+	key.API = "CreateProduct"
+	assert.NoError(t, l.Wait(context.Background(), key))
+}
+
+// fakeClock is a clock for the limiter that only moves when told to
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestTokenBucketLimiter_Spacing(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	l := NewTokenBucketLimiter(Limit{Rate: 10, Burst: 2}, Limit{})
+	l.now = clock.now
+	key := RateLimitKey{AppKey: "app"}
+
+	// The burst goes through at once, then calls queue up 100ms apart
+	delays := []time.Duration{}
+	for i := 0; i < 5; i++ {
+		d, _ := l.reserve(key)
+		delays = append(delays, d)
+	}
+	assert.Equal(t, []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, delays)
+
+	// After a second the bucket is full again and allows another burst
+	clock.advance(time.Second)
+	d, _ := l.reserve(key)
+	assert.Equal(t, time.Duration(0), d)
+	d, _ = l.reserve(key)
+	assert.Equal(t, time.Duration(0), d)
+	d, _ = l.reserve(key)
+	assert.Equal(t, 100*time.Millisecond, d)
+}
+
+func TestTokenBucketLimiter_APILimitChanged(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	l := NewTokenBucketLimiter(Limit{}, Limit{})
+	l.now = clock.now
+	key := RateLimitKey{AppKey: "app", AccessToken: "seller", API: "CreateProduct"}
+
+	l.SetAPILimit("CreateProduct", Limit{Rate: 1, Burst: 1})
+	d, _ := l.reserve(key)
+	assert.Equal(t, time.Duration(0), d)
+	d, _ = l.reserve(key)
+	assert.Equal(t, time.Second, d)
+
+	// The new limit applies to the existing bucket
+	clock.advance(2 * time.Second)
+	l.SetAPILimit("CreateProduct", Limit{Rate: 4, Burst: 1})
+	d, _ = l.reserve(key)
+	assert.Equal(t, time.Duration(0), d)
+	d, _ = l.reserve(key)
+	assert.Equal(t, 250*time.Millisecond, d)
+}
+
+func TestTokenBucketLimiter_IdleBucketsDropped(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	l := NewTokenBucketLimiter(Limit{}, Limit{Rate: 0.01, Burst: 1})
+	l.now = clock.now
+	seller := func(i int) RateLimitKey { return RateLimitKey{AppKey: "app", AccessToken: fmt.Sprintf("seller%d", i)} }
+
+	for i := 0; i < 10; i++ {
+		l.reserve(seller(i))
+	}
+	assert.Len(t, l.buckets, 10)
+
+	// seller0 keeps calling so its bucket is still in debt when the others have refilled
+	clock.advance(50 * time.Second)
+	l.reserve(seller(0))
+
+	clock.advance(bucketSweepInterval)
+	l.reserve(seller(1))
+	assert.Len(t, l.buckets, 2)
+	assert.Contains(t, l.buckets, "seller:app:seller0")
+}
+
+func TestClient_RateLimiterShared(t *testing.T) {
+	l := NewTokenBucketLimiter(Limit{}, Limit{})
+	c, err := NewClient("123456", "testsecretnotarealsecret", Singapore, WithRateLimiter(l))
+	require.NoError(t, err)
+
+	assert.Equal(t, RateLimiter(l), c.NewTokenClient("token").limiter)
+}
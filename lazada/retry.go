@@ -0,0 +1,131 @@
+package lazada
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RetryPolicy decides which failed calls are attempted again and how long to wait in between.
+//
+// Calls rejected because of a call limit never reached the API so they are always safe to retry.
+// Other transient failures such as timeouts or 5xx responses might have been processed already,
+// those are only retried for idempotent calls, GET requests and any API listed in IdempotentAPIs.
+type RetryPolicy struct {
+	// The maximum number of attempts including the first one
+	MaxAttempts int
+
+	// The delay before the first retry, every following retry doubles it
+	BaseDelay time.Duration
+
+	// The upper limit for the delay between retries
+	MaxDelay time.Duration
+
+	// Error codes that mean the call was rejected before being processed
+	ThrottledCodes []string
+
+	// Error codes that mean the call failed but might have been processed
+	TransientCodes []string
+
+	// Names from apiNames of POST calls that can be safely replayed
+	IdempotentAPIs []string
+}
+
+// DefaultRetryPolicy retries up to three times starting with a half second delay
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	BaseDelay:      500 * time.Millisecond,
+	MaxDelay:       10 * time.Second,
+	ThrottledCodes: []string{"ApiCallLimit", "AppCallLimit"},
+	TransientCodes: []string{"ServiceTimeout", "ServiceUnavailable", "InternalError"},
+	IdempotentAPIs: []string{"UpdatePriceQuantity"},
+}
+
+var (
+	jitterMu sync.Mutex
+	jitter   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// shouldRetry reports if a call to the api should be attempted again after attempt failed with err
+func (p *RetryPolicy) shouldRetry(method, api string, attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	// The context is done so there is no point trying again
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+
+	errResp, ok := err.(*ErrorResponse)
+	if !ok {
+		// Transport errors, the request might have been sent
+		return p.idempotent(method, api)
+	}
+
+	if contains(p.ThrottledCodes, errResp.Code) {
+		return true
+	}
+
+	if errResp.Response != nil {
+		switch c := errResp.Response.StatusCode; {
+		case c == http.StatusTooManyRequests:
+			return true
+		case c >= 500:
+			return p.idempotent(method, api)
+		}
+	}
+
+	return contains(p.TransientCodes, errResp.Code) && p.idempotent(method, api)
+}
+
+func (p *RetryPolicy) idempotent(method, api string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+
+	return contains(p.IdempotentAPIs, api)
+}
+
+// backoff returns the delay before the retry following attempt.
+// The delay grows exponentially and a random jitter of up to half of it is removed.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << uint(attempt-1)
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+
+	if half := int64(d / 2); half > 0 {
+		jitterMu.Lock()
+		d -= time.Duration(jitter.Int63n(half))
+		jitterMu.Unlock()
+	}
+
+	return d
+}
+
+// wait sleeps for the backoff delay or until the context is done
+func (p *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	t := time.NewTimer(p.backoff(attempt))
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
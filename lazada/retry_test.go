@@ -0,0 +1,74 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	BaseDelay:      time.Millisecond,
+	MaxDelay:       5 * time.Millisecond,
+	ThrottledCodes: DefaultRetryPolicy.ThrottledCodes,
+	TransientCodes: DefaultRetryPolicy.TransientCodes,
+}
+
+func TestClient_RetryThrottled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.retry = &testRetryPolicy
+
+	calls := 0
+	mux.HandleFunc("/rest/product/create", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		require.NoError(t, r.ParseForm())
+		assert.NotEmpty(t, r.PostForm.Get("payload"))
+
+		// Every attempt must carry a valid signature
+		sign := r.PostForm.Get("sign")
+		r.PostForm.Del("sign")
+		assert.Equal(t, client.Signature("/product/create", r.PostForm, nil), sign)
+
+		if calls == 1 {
+			fmt.Fprint(w, `{"code":"ApiCallLimit","type":"ISP","message":"slow down"}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":"0","data":{"item_id":1}}`)
+	})
+
+	resp, err := client.Products.Create(context.Background(), &Product{PrimaryCategory: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.ItemID)
+	assert.Equal(t, 2, calls)
+}
+
+func TestClient_RetryNotIdempotent(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.retry = &testRetryPolicy
+
+	calls := 0
+	mux.HandleFunc("/rest/product/create", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"code":"ServiceTimeout","type":"ISP","message":"timeout"}`)
+	})
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := client.Products.Create(context.Background(), &Product{PrimaryCategory: "1"})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	_, err = client.Products.Brands(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,69 @@
+package lazada
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup makes sure only one call for a key is running at a time,
+// concurrent callers for the same key wait for and share its result.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+// do runs fn unless a call for key is already running in which case it waits for that one.
+// fn runs on a context detached from the caller that started it so a single caller giving up
+// doesn't fail the others, every caller stops waiting when its own ctx is done.
+// The context of fn is cancelled after timeout so a call that hangs can't hold the key forever.
+func (g *flightGroup) do(ctx context.Context, key string, timeout time.Duration, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+
+		fnCtx, cancel := context.WithTimeout(detachedContext{parent: ctx}, timeout)
+		go func() {
+			defer cancel()
+			call.val, call.err = fn(fnCtx)
+
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// detachedContext keeps the values of its parent but is never cancelled and has no deadline
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
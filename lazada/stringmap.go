@@ -1,22 +1,24 @@
 package lazada
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+)
 
 type StringMap map[string]string
 
 // StringMap marshals a map into XML.
+// The elements are written in the order of their names so the same map always gives the same XML.
 func (s StringMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-
-	tokens := []xml.Token{}
-
-	for key, value := range s {
-		t := xml.StartElement{Name: xml.Name{Space: "", Local: key}}
-		tokens = append(tokens, t, xml.CharData(value), xml.EndElement{Name: t.Name})
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	for _, t := range tokens {
-		err := e.EncodeToken(t)
-		if err != nil {
+	for _, key := range keys {
+		if err := encodeText(e, key, s[key]); err != nil {
 			return err
 		}
 	}
@@ -29,3 +31,99 @@ func (s StringMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 
 	return nil
 }
+
+// Element is an XML element holding either text or child elements.
+// It is used for attributes a StringMap can't express such as lists of values.
+type Element struct {
+	Name     string
+	Value    string
+	Children Elements
+}
+
+// NewElement returns an element with text
+func NewElement(name, value string) *Element {
+	return &Element{Name: name, Value: value}
+}
+
+// NewList returns an element holding an item element for every value,
+// e.g. NewList("images", "image", a, b) gives <images><image>a</image><image>b</image></images>
+func NewList(name, item string, values ...string) *Element {
+	el := &Element{Name: name}
+	for _, v := range values {
+		el.Children = append(el.Children, NewElement(item, v))
+	}
+
+	return el
+}
+
+// Elements is a list of elements written in order, the same name can be repeated
+type Elements []*Element
+
+// Elements marshals the elements into XML in order
+func (es Elements) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	for _, el := range es {
+		if err := el.encode(e); err != nil {
+			return err
+		}
+	}
+
+	// flush to ensure tokens are written
+	return e.Flush()
+}
+
+func (el *Element) encode(e *xml.Encoder) error {
+	if len(el.Children) == 0 {
+		return encodeText(e, el.Name, el.Value)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: el.Name}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, child := range el.Children {
+		if err := child.encode(e); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// Get returns the text of the first element with the name.
+// The values of the children are joined with commas for list elements.
+func (es Elements) Get(name string) string {
+	for _, el := range es {
+		if el.Name != name {
+			continue
+		}
+
+		if len(el.Children) == 0 {
+			return el.Value
+		}
+
+		values := make([]string, len(el.Children))
+		for i, child := range el.Children {
+			values[i] = child.Value
+		}
+		return strings.Join(values, ",")
+	}
+
+	return ""
+}
+
+// cdata is used to write text holding markup without escaping it
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// encodeText writes an element with text, HTML such as descriptions is wrapped in CDATA
+func encodeText(e *xml.Encoder, name, value string) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	if strings.Contains(value, "<") {
+		return e.EncodeElement(cdata{Value: value}, start)
+	}
+
+	return e.EncodeElement(value, start)
+}
@@ -0,0 +1,49 @@
+package lazada
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringMap_MarshalXML(t *testing.T) {
+	attrs := &Attributes{Attrs: StringMap{
+		"name":        "Tom & Jerry",
+		"brand":       "No Brand",
+		"description": "<p>Soft <b>cotton</b></p>",
+	}}
+
+	for i := 0; i < 10; i++ {
+		out, err := xml.Marshal(attrs)
+		require.NoError(t, err)
+		assert.Equal(t, "<Attributes><brand>No Brand</brand>"+
+			"<description><![CDATA[<p>Soft <b>cotton</b></p>]]></description>"+
+			"<name>Tom &amp; Jerry</name></Attributes>", string(out))
+	}
+}
+
+func TestElements_MarshalXML(t *testing.T) {
+	sku := &Sku{
+		SellerSku: "a",
+		SkuAttrs:  StringMap{"price": "1.0"},
+		Extra: Elements{
+			NewList("images", "image", "https://a.jpg", "https://b.jpg"),
+			NewElement("size", "M"),
+			NewElement("size", "L"),
+			{Name: "bundle", Children: Elements{NewElement("sku", "b"), NewList("gifts", "gift", "<i>card</i>")}},
+		},
+	}
+
+	out, err := xml.Marshal(sku)
+	require.NoError(t, err)
+	assert.Equal(t, "<Sku><SellerSku>a</SellerSku><price>1.0</price>"+
+		"<images><image>https://a.jpg</image><image>https://b.jpg</image></images>"+
+		"<size>M</size><size>L</size>"+
+		"<bundle><sku>b</sku><gifts><gift><![CDATA[<i>card</i>]]></gift></gifts></bundle></Sku>", string(out))
+
+	assert.Equal(t, "https://a.jpg,https://b.jpg", sku.Extra.Get("images"))
+	assert.Equal(t, "M", sku.Extra.Get("size"))
+	assert.Equal(t, "", sku.Extra.Get("missing"))
+}
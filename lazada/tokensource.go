@@ -0,0 +1,115 @@
+package lazada
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TokenSource supplies the access token for every call made by a client created with NewTokenSourceClient
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// DefaultRefreshBefore is how long before expiry a RefreshTokenSource refreshes its token
+const DefaultRefreshBefore = 10 * time.Minute
+
+// DefaultRefreshTimeout is how long a RefreshTokenSource waits for a refresh before giving up
+const DefaultRefreshTimeout = time.Minute
+
+// RefreshTokenSource returns its token until it is about to expire and then refreshes it.
+// It is safe to use from multiple goroutines, only a single refresh runs at a time
+// and every caller waiting on it receives the new token.
+type RefreshTokenSource struct {
+	auth      *AuthService
+	onRefresh func(*Token)
+
+	// How long before the token expires it should be refreshed, defaults to DefaultRefreshBefore.
+	// It must be set before the token source is used.
+	RefreshBefore time.Duration
+
+	// How long a refresh can take, defaults to DefaultRefreshTimeout.
+	// The refresh is shared by every caller so it isn't bound by their contexts, this stops a hung
+	// auth host blocking every refresh after it. It must be set before the token source is used.
+	RefreshTimeout time.Duration
+
+	mu     sync.Mutex
+	token  *Token
+	flight flightGroup
+}
+
+// TokenSource returns a token source that starts with t and refreshes it through this auth service.
+// onRefresh is called with every new token so the refresh token can be persisted, it can be nil.
+//
+// Use the auth service of a client without a token source as the refresh does not need an access token.
+func (a *AuthService) TokenSource(t *Token, onRefresh func(*Token)) *RefreshTokenSource {
+	return &RefreshTokenSource{
+		auth:           a,
+		onRefresh:      onRefresh,
+		token:          t,
+		RefreshBefore:  DefaultRefreshBefore,
+		RefreshTimeout: DefaultRefreshTimeout,
+	}
+}
+
+// Token returns a token that is valid for at least RefreshBefore, refreshing it if needed
+func (s *RefreshTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	t := s.token
+	s.mu.Unlock()
+
+	if t != nil && t.AccessToken != "" && time.Now().Add(s.RefreshBefore).Before(t.ExpiresAt()) {
+		return t, nil
+	}
+
+	if t == nil || t.RefreshToken == "" {
+		return nil, errors.New("token expired and there is no refresh token")
+	}
+
+	timeout := s.RefreshTimeout
+	if timeout <= 0 {
+		timeout = DefaultRefreshTimeout
+	}
+
+	// The refresh is shared by every caller waiting for it so it doesn't use the context of any single one
+	v, err := s.flight.do(ctx, "refresh", timeout, func(ctx context.Context) (interface{}, error) {
+		// Another caller could have refreshed while we were checking
+		s.mu.Lock()
+		current := s.token
+		s.mu.Unlock()
+		if current != t {
+			return current, nil
+		}
+
+		newToken, err := s.auth.Refresh(ctx, t.RefreshToken)
+		if err != nil {
+			return nil, errors.Wrap(err, "cant refresh token")
+		}
+
+		s.mu.Lock()
+		s.token = newToken
+		s.mu.Unlock()
+
+		if s.onRefresh != nil {
+			s.onRefresh(newToken)
+		}
+
+		return newToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*Token), nil
+}
+
+// NewTokenSourceClient returns a copy of the client that gets its access token from ts before every call
+func (c *Client) NewTokenSourceClient(ts TokenSource) *Client {
+	newC := *c
+	newC.accessToken = ""
+	newC.tokenSource = ts
+	initServices(&newC)
+	return &newC
+}
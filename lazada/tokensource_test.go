@@ -0,0 +1,121 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokenSource(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	refreshes := 0
+	mux.HandleFunc("/rest/auth/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		assert.Equal(t, "refresh1", r.URL.Query().Get("refresh_token"))
+		fmt.Fprint(w, `{"code":"0","access_token":"access2","refresh_token":"refresh2","expires_in":3600,"refresh_expires_in":7200}`)
+	})
+	mux.HandleFunc("/rest/brands/get", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "access2", r.URL.Query().Get("access_token"))
+		fmt.Fprint(w, `{"code":"0","data":[]}`)
+	})
+
+	var persisted *Token
+	expired := &Token{AccessToken: "access1", RefreshToken: "refresh1", ExpiresIn: 60, RetrievedAt: time.Now()}
+	ts := client.Auth.TokenSource(expired, func(t *Token) { persisted = t })
+
+	tsClient := client.NewTokenSourceClient(ts)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tsClient.Products.Brands(context.Background(), nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, refreshes)
+	require.NotNil(t, persisted)
+	assert.Equal(t, "refresh2", persisted.RefreshToken)
+	assert.True(t, persisted.Valid())
+}
+
+func TestRefreshTokenSource_CallerCancelled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	release := make(chan struct{})
+	mux.HandleFunc("/rest/auth/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprint(w, `{"code":"0","access_token":"access2","refresh_token":"refresh2","expires_in":3600,"refresh_expires_in":7200}`)
+	})
+
+	refreshed := make(chan *Token, 1)
+	expired := &Token{AccessToken: "access1", RefreshToken: "refresh1", ExpiresIn: 60, RetrievedAt: time.Now()}
+	ts := client.Auth.TokenSource(expired, func(t *Token) { refreshed <- t })
+
+	// The caller that started the refresh gives up while it is running
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := ts.Token(ctx)
+		first <- err
+	}()
+	cancel()
+	assert.Equal(t, context.Canceled, <-first)
+
+	second := make(chan *Token, 1)
+	go func() {
+		tok, err := ts.Token(context.Background())
+		assert.NoError(t, err)
+		second <- tok
+	}()
+
+	close(release)
+	assert.Equal(t, "access2", (<-second).AccessToken)
+	assert.Equal(t, "refresh2", (<-refreshed).RefreshToken)
+}
+
+func TestRefreshTokenSource_HungRefresh(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	hung := make(chan struct{})
+	defer close(hung)
+
+	refreshes := 0
+	mux.HandleFunc("/rest/auth/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		if refreshes == 1 {
+			select {
+			case <-hung:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		fmt.Fprint(w, `{"code":"0","access_token":"access2","refresh_token":"refresh2","expires_in":3600,"refresh_expires_in":7200}`)
+	})
+
+	expired := &Token{AccessToken: "access1", RefreshToken: "refresh1", ExpiresIn: 60, RetrievedAt: time.Now()}
+	ts := client.Auth.TokenSource(expired, nil)
+	ts.RefreshTimeout = 50 * time.Millisecond
+
+	// The refresh that hangs times out even though the caller has no deadline
+	_, err := ts.Token(context.Background())
+	assert.Error(t, err)
+
+	// and doesn't block the refreshes after it
+	tok, err := ts.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "access2", tok.AccessToken)
+}
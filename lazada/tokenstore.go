@@ -0,0 +1,306 @@
+package lazada
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrTokenNotFound is returned by a TokenStore when there is no token for the account
+var ErrTokenNotFound = errors.New("lazada: token not found")
+
+// TokenStore persists seller tokens keyed by the country and account id of the token
+type TokenStore interface {
+	// Get returns the token for the account or ErrTokenNotFound
+	Get(ctx context.Context, country, accountID string) (*Token, error)
+
+	// Put saves the token replacing any previous token for the same account
+	Put(ctx context.Context, t *Token) error
+
+	// Delete removes the token for the account, deleting a missing token is not an error
+	Delete(ctx context.Context, country, accountID string) error
+
+	// List returns all the stored tokens
+	List(ctx context.Context) ([]*Token, error)
+}
+
+type tokenKey struct {
+	country   string
+	accountID string
+}
+
+func keyOf(t *Token) tokenKey {
+	return tokenKey{country: strings.ToLower(t.Country), accountID: t.AccountID}
+}
+
+// MemoryTokenStore keeps tokens in memory, it is safe for concurrent use
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[tokenKey]Token
+}
+
+// NewMemoryTokenStore returns an empty in memory token store
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: make(map[tokenKey]Token)}
+}
+
+// Get implements TokenStore
+func (s *MemoryTokenStore) Get(ctx context.Context, country, accountID string) (*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tokens[tokenKey{country: strings.ToLower(country), accountID: accountID}]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	return &t, nil
+}
+
+// Put implements TokenStore
+func (s *MemoryTokenStore) Put(ctx context.Context, t *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[keyOf(t)] = *t
+	return nil
+}
+
+// Delete implements TokenStore
+func (s *MemoryTokenStore) Delete(ctx context.Context, country, accountID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, tokenKey{country: strings.ToLower(country), accountID: accountID})
+	return nil
+}
+
+// List implements TokenStore
+func (s *MemoryTokenStore) List(ctx context.Context) ([]*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*Token, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		t := t
+		out = append(out, &t)
+	}
+
+	return out, nil
+}
+
+// FileTokenStore keeps tokens in a JSON file.
+// The file is rewritten atomically on every change and is only readable by the current user.
+// It is safe for concurrent use within a single process.
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileTokenStore returns a store using the file at path, the file is created on the first Put
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Get implements TokenStore
+func (s *FileTokenStore) Get(ctx context.Context, country, accountID string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tokens {
+		if keyOf(t) == (tokenKey{country: strings.ToLower(country), accountID: accountID}) {
+			return t, nil
+		}
+	}
+
+	return nil, ErrTokenNotFound
+}
+
+// Put implements TokenStore
+func (s *FileTokenStore) Put(ctx context.Context, t *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, existing := range tokens {
+		if keyOf(existing) == keyOf(t) {
+			tokens[i] = t
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		tokens = append(tokens, t)
+	}
+
+	return s.save(tokens)
+}
+
+// Delete implements TokenStore
+func (s *FileTokenStore) Delete(ctx context.Context, country, accountID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	key := tokenKey{country: strings.ToLower(country), accountID: accountID}
+	kept := tokens[:0]
+	for _, t := range tokens {
+		if keyOf(t) != key {
+			kept = append(kept, t)
+		}
+	}
+
+	return s.save(kept)
+}
+
+// List implements TokenStore
+func (s *FileTokenStore) List(ctx context.Context) ([]*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+func (s *FileTokenStore) load() ([]*Token, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return []*Token{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "cant read token file")
+	}
+
+	tokens := []*Token{}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, errors.Wrap(err, "cant decode token file")
+	}
+
+	return tokens, nil
+}
+
+func (s *FileTokenStore) save(tokens []*Token) error {
+	sort.Slice(tokens, func(i, j int) bool {
+		a, b := keyOf(tokens[i]), keyOf(tokens[j])
+		if a.country != b.country {
+			return a.country < b.country
+		}
+		return a.accountID < b.accountID
+	})
+
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cant encode tokens")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "cant create token file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "cant write token file")
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "cant write token file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), s.path), "cant replace token file")
+}
+
+// NewStoreClient returns a client for the seller using the token saved in store.
+// A client set to a region switches to the region matching the country of the token,
+// a client using a custom base url keeps it. The token is refreshed
+// when it is about to expire and the refreshed token is saved back to the store.
+//
+// If a refreshed token can't be saved the call that refreshed it fails with the error
+// and saving is tried again on the next call, the new token is still used in the meantime.
+func (c *Client) NewStoreClient(ctx context.Context, store TokenStore, country, accountID string) (*Client, error) {
+	t, err := store.Get(ctx, country, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	newC := c.NewTokenSourceClient(&storeTokenSource{
+		ts:        c.Auth.TokenSource(t, nil),
+		store:     store,
+		accountID: t.AccountID,
+		country:   t.Country,
+		saved:     t,
+	})
+
+	// A custom base url, e.g. a test server or a proxy, must not be swapped for the real endpoint
+	if c.region != "" && t.Country != "" {
+		if err := newC.SetRegion(Region(strings.ToLower(t.Country))); err != nil {
+			return nil, err
+		}
+	}
+
+	return newC, nil
+}
+
+// storeTokenSource saves every token refreshed by ts to store
+type storeTokenSource struct {
+	ts    *RefreshTokenSource
+	store TokenStore
+
+	// The refresh response is not guaranteed to repeat the account details
+	accountID string
+	country   string
+
+	mu    sync.Mutex
+	saved *Token
+}
+
+// Token implements TokenSource
+func (s *storeTokenSource) Token(ctx context.Context) (*Token, error) {
+	t, err := s.ts.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t == s.saved {
+		return t, nil
+	}
+
+	refreshed := *t
+	if refreshed.AccountID == "" {
+		refreshed.AccountID = s.accountID
+	}
+	if refreshed.Country == "" {
+		refreshed.Country = s.country
+	}
+
+	if err := s.store.Put(ctx, &refreshed); err != nil {
+		return nil, errors.Wrap(err, "cant save refreshed token")
+	}
+
+	s.saved = t
+	return t, nil
+}
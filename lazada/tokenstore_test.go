@@ -0,0 +1,137 @@
+package lazada
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazada")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	retrieved := time.Now().Add(-time.Hour).Truncate(time.Second)
+	token := &Token{AccountID: "100", Country: "sg", AccessToken: "access", RefreshToken: "refresh",
+		ExpiresIn: 7200, RetrievedAt: retrieved}
+
+	store := NewFileTokenStore(filepath.Join(dir, "tokens.json"))
+	require.NoError(t, store.Put(ctx, token))
+	require.NoError(t, store.Put(ctx, &Token{AccountID: "200", Country: "my"}))
+
+	// A new store reading the same file sees the same tokens
+	store = NewFileTokenStore(filepath.Join(dir, "tokens.json"))
+	got, err := store.Get(ctx, "SG", "100")
+	require.NoError(t, err)
+	assert.Equal(t, "access", got.AccessToken)
+	assert.True(t, token.ExpiresAt().Equal(got.ExpiresAt()))
+	assert.True(t, got.Valid())
+
+	require.NoError(t, store.Delete(ctx, "sg", "100"))
+	_, err = store.Get(ctx, "sg", "100")
+	assert.Equal(t, ErrTokenNotFound, err)
+
+	all, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestClient_NewStoreClient(t *testing.T) {
+	store := NewMemoryTokenStore()
+	store.Put(context.Background(), &Token{AccountID: "100", Country: "my", AccessToken: "access",
+		ExpiresIn: 7200, RetrievedAt: time.Now()})
+
+	c, err := NewClient("123456", "testsecretnotarealsecret", Singapore)
+	require.NoError(t, err)
+	seller, err := c.NewStoreClient(context.Background(), store, "my", "100")
+	require.NoError(t, err)
+	assert.Equal(t, endpoints[Malaysia], seller.BaseURL.String())
+
+	tok, err := seller.token(context.Background(), "GetProducts")
+	require.NoError(t, err)
+	assert.Equal(t, "access", tok)
+
+	_, err = c.NewStoreClient(context.Background(), store, "sg", "100")
+	assert.Equal(t, ErrTokenNotFound, err)
+}
+
+func TestClient_NewStoreClient_BaseURL(t *testing.T) {
+	store := NewMemoryTokenStore()
+	store.Put(context.Background(), &Token{AccountID: "100", Country: "my", AccessToken: "access",
+		ExpiresIn: 7200, RetrievedAt: time.Now()})
+
+	c, err := NewClient("123456", "testsecretnotarealsecret", Singapore, WithBaseURL("http://127.0.0.1:9999/"))
+	require.NoError(t, err)
+
+	seller, err := c.NewStoreClient(context.Background(), store, "my", "100")
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:9999/", seller.BaseURL.String())
+	assert.Equal(t, Region(""), seller.Region())
+}
+
+// failingStore fails the next fails calls to Put
+type failingStore struct {
+	*MemoryTokenStore
+	fails int
+}
+
+func (s *failingStore) Put(ctx context.Context, t *Token) error {
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("disk full")
+	}
+	return s.MemoryTokenStore.Put(ctx, t)
+}
+
+func TestClient_NewStoreClient_SaveFails(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rest/auth/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":"0","access_token":"access2","refresh_token":"refresh2","expires_in":3600,"refresh_expires_in":7200}`)
+	})
+
+	ctx := context.Background()
+	store := &failingStore{MemoryTokenStore: NewMemoryTokenStore()}
+	store.MemoryTokenStore.Put(ctx, &Token{AccountID: "100", Country: "sg", AccessToken: "access1",
+		RefreshToken: "refresh1", ExpiresIn: 60, RetrievedAt: time.Now()})
+	store.fails = 1
+
+	seller, err := client.NewStoreClient(ctx, store, "sg", "100")
+	require.NoError(t, err)
+
+	// The call that refreshed reports the token could not be saved
+	_, err = seller.token(ctx, "GetProducts")
+	assert.EqualError(t, err, "cant save refreshed token: disk full")
+
+	// The next call saves it
+	tok, err := seller.token(ctx, "GetProducts")
+	require.NoError(t, err)
+	assert.Equal(t, "access2", tok)
+
+	saved, err := store.Get(ctx, "sg", "100")
+	require.NoError(t, err)
+	assert.Equal(t, "refresh2", saved.RefreshToken)
+}
+
+func TestClient_NewStoreClient_UnknownCountry(t *testing.T) {
+	store := NewMemoryTokenStore()
+	store.Put(context.Background(), &Token{AccountID: "100", Country: "xx", AccessToken: "access",
+		ExpiresIn: 7200, RetrievedAt: time.Now()})
+
+	c, err := NewClient("123456", "testsecretnotarealsecret", Singapore)
+	require.NoError(t, err)
+
+	_, err = c.NewStoreClient(context.Background(), store, "xx", "100")
+	assert.True(t, errors.Is(err, ErrUnknownRegion))
+}
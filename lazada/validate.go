@@ -0,0 +1,181 @@
+package lazada
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AttributeSource returns the attributes of a category, it is implemented by ProductService
+type AttributeSource interface {
+	CategoryAttributes(ctx context.Context, id int) ([]*CategoryAttributes, error)
+}
+
+// ValidationProblem is a single problem found in a product by ValidateProduct
+type ValidationProblem struct {
+	// The seller sku the problem is about, empty for product attributes
+	SellerSKU string
+
+	// The name of the attribute, empty if the problem is about the whole product
+	Attribute string
+
+	Message string
+}
+
+func (p *ValidationProblem) String() string {
+	msg := p.Message
+	if p.Attribute != "" {
+		msg = p.Attribute + ": " + msg
+	}
+	if p.SellerSKU != "" {
+		msg = "sku " + p.SellerSKU + ": " + msg
+	}
+
+	return msg
+}
+
+// Input types whose values must be one of the options of the attribute
+const (
+	inputSingleSelect = "singleSelect"
+	inputMultiSelect  = "multiSelect"
+)
+
+// attributeTypeSKU is the type of attributes set on each sku instead of on the product
+const attributeTypeSKU = "sku"
+
+// Validate checks a product against the attributes of its primary category before it is created, see ValidateProduct
+func (p *ProductService) Validate(ctx context.Context, product *Product) ([]*ValidationProblem, error) {
+	return ValidateProduct(ctx, p, product)
+}
+
+// ValidateProduct checks a product against the attributes of its primary category fetched from src.
+// It checks that mandatory attributes are set, that select attributes use one of their options
+// and that every sku sets the sale properties that make it a distinct variation.
+// All the problems found are returned, an error is only returned if the attributes could not be fetched.
+func ValidateProduct(ctx context.Context, src AttributeSource, product *Product) ([]*ValidationProblem, error) {
+	category, err := strconv.Atoi(product.PrimaryCategory)
+	if err != nil || category <= 0 {
+		return []*ValidationProblem{{Attribute: "PrimaryCategory", Message: "must be a category id"}}, nil
+	}
+
+	attrs, err := src.CategoryAttributes(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []*ValidationProblem{}
+	add := func(sku, attr, format string, args ...interface{}) {
+		problems = append(problems, &ValidationProblem{SellerSKU: sku, Attribute: attr, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(product.Skus) == 0 {
+		add("", "Skus", "at least one sku is required")
+	}
+
+	productValue := func(name string) string {
+		if product.Attributes == nil {
+			return ""
+		}
+		if v, ok := product.Attributes.Attrs[name]; ok {
+			return v
+		}
+		return product.Attributes.Extra.Get(name)
+	}
+
+	var saleProps []string
+	for _, a := range attrs {
+		if a.IsSale == 1 {
+			saleProps = append(saleProps, a.Name)
+		}
+
+		if a.AttributeType != attributeTypeSKU && a.IsSale != 1 {
+			checkAttribute(a, "", productValue(a.Name), add)
+			continue
+		}
+
+		for _, sku := range product.Skus {
+			checkAttribute(a, sku.SellerSku, skuValue(sku, a.Name), add)
+		}
+	}
+
+	// Skus are variations of the product so their sale properties have to tell them apart
+	if len(product.Skus) > 1 && len(saleProps) > 0 {
+		seen := map[string]string{}
+		for _, sku := range product.Skus {
+			values := make([]string, len(saleProps))
+			for i, name := range saleProps {
+				values[i] = skuValue(sku, name)
+			}
+
+			key := strings.Join(values, "\x00")
+			if other, ok := seen[key]; ok {
+				add(sku.SellerSku, "", "has the same sale properties (%s) as sku %s", strings.Join(saleProps, ", "), other)
+				continue
+			}
+			seen[key] = sku.SellerSku
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].SellerSKU < problems[j].SellerSKU })
+	return problems, nil
+}
+
+// checkAttribute checks a single value of an attribute
+func checkAttribute(a *CategoryAttributes, sku, value string, add func(sku, attr, format string, args ...interface{})) {
+	if strings.TrimSpace(value) == "" {
+		switch {
+		case a.IsMandatory == 1:
+			add(sku, a.Name, "is mandatory")
+		case a.IsSale == 1 && sku != "":
+			add(sku, a.Name, "is a sale property and must be set on every sku")
+		}
+		return
+	}
+
+	if len(a.Options) == 0 || (a.InputType != inputSingleSelect && a.InputType != inputMultiSelect) {
+		return
+	}
+
+	values := []string{value}
+	if a.InputType == inputMultiSelect {
+		values = strings.Split(value, ",")
+	}
+
+	for _, v := range values {
+		if !hasOption(a.Options, strings.TrimSpace(v)) {
+			add(sku, a.Name, "%q is not one of the options", strings.TrimSpace(v))
+		}
+	}
+}
+
+func hasOption(options []*CategoryOptions, v string) bool {
+	for _, o := range options {
+		if o.Name == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skuValue returns the value of an attribute on a sku including the ones that are fields of Sku.
+// List attributes in Extra are returned as comma separated values.
+func skuValue(sku *Sku, name string) string {
+	switch name {
+	case "SellerSku":
+		return sku.SellerSku
+	case "Images":
+		if sku.Images != nil && len(sku.Images.Image) > 0 {
+			return sku.Images.Image[0]
+		}
+		return ""
+	}
+
+	if v, ok := sku.SkuAttrs[name]; ok {
+		return v
+	}
+
+	return sku.Extra.Get(name)
+}
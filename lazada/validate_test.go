@@ -0,0 +1,87 @@
+package lazada
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAttributes []*CategoryAttributes
+
+func (f fakeAttributes) CategoryAttributes(ctx context.Context, id int) ([]*CategoryAttributes, error) {
+	if id != 10 {
+		return nil, errors.New("unknown category")
+	}
+
+	return f, nil
+}
+
+var testAttributes = fakeAttributes{
+	{Name: "name", IsMandatory: 1, AttributeType: "normal", InputType: "text"},
+	{Name: "brand", IsMandatory: 1, AttributeType: "normal", InputType: "singleSelect",
+		Options: []*CategoryOptions{{Name: "Kid Basix"}, {Name: "No Brand"}}},
+	{Name: "material", AttributeType: "normal", InputType: "multiSelect",
+		Options: []*CategoryOptions{{Name: "Cotton"}, {Name: "Wool"}}},
+	{Name: "SellerSku", IsMandatory: 1, AttributeType: "sku", InputType: "text"},
+	{Name: "price", IsMandatory: 1, AttributeType: "sku", InputType: "numeric"},
+	{Name: "color_family", IsSale: 1, AttributeType: "sku", InputType: "singleSelect",
+		Options: []*CategoryOptions{{Name: "Black"}, {Name: "Red"}}},
+}
+
+func TestValidateProduct(t *testing.T) {
+	product := &Product{
+		PrimaryCategory: "10",
+		Attributes:      &Attributes{Attrs: StringMap{"brand": "Unknown", "material": "Cotton, Silk"}},
+		Skus: []*Sku{
+			{SellerSku: "a", SkuAttrs: StringMap{"price": "1", "color_family": "Black"}},
+			{SellerSku: "b", SkuAttrs: StringMap{"color_family": "Black"}},
+			{SellerSku: "c", SkuAttrs: StringMap{"price": "1"}},
+		},
+	}
+
+	problems, err := ValidateProduct(context.Background(), testAttributes, product)
+	require.NoError(t, err)
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+
+	assert.Equal(t, []string{
+		"name: is mandatory",
+		`brand: "Unknown" is not one of the options`,
+		`material: "Silk" is not one of the options`,
+		"sku b: price: is mandatory",
+		"sku b: has the same sale properties (color_family) as sku a",
+		"sku c: color_family: is a sale property and must be set on every sku",
+	}, got)
+}
+
+func TestValidateProduct_Valid(t *testing.T) {
+	product := &Product{
+		PrimaryCategory: "10",
+		Attributes: &Attributes{
+			Attrs: StringMap{"name": "test", "brand": "No Brand"},
+			Extra: Elements{NewList("material", "value", "Cotton", "Wool")},
+		},
+		Skus: []*Sku{
+			{SellerSku: "a", SkuAttrs: StringMap{"price": "1", "color_family": "Black"}},
+			{SellerSku: "b", SkuAttrs: StringMap{"price": "1", "color_family": "Red"}},
+		},
+	}
+
+	problems, err := ValidateProduct(context.Background(), testAttributes, product)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	problems, err = ValidateProduct(context.Background(), testAttributes, &Product{PrimaryCategory: "x"})
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Equal(t, "PrimaryCategory", problems[0].Attribute)
+
+	_, err = ValidateProduct(context.Background(), testAttributes, &Product{PrimaryCategory: "11"})
+	assert.Error(t, err)
+}
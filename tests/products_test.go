@@ -15,8 +15,14 @@ import (
 var AppKey = os.Getenv("APP_KEY")
 var AppSecret = os.Getenv("APP_SECRET")
 
+func newClient(t *testing.T, region lazada.Region) *lazada.Client {
+	c, err := lazada.NewClient(AppKey, AppSecret, region)
+	require.NoError(t, err)
+	return c
+}
+
 func TestProductBrands(t *testing.T) {
-	c := lazada.NewClient(AppKey, AppSecret, lazada.Singapore)
+	c := newClient(t, lazada.Singapore)
 	br, err := c.Products.Brands(context.Background(), nil)
 	require.NoError(t, err)
 
@@ -24,7 +30,7 @@ func TestProductBrands(t *testing.T) {
 }
 
 func TestListOptions(t *testing.T) {
-	c := lazada.NewClient(AppKey, AppSecret, lazada.Singapore)
+	c := newClient(t, lazada.Singapore)
 	br, err := c.Products.Brands(context.Background(), &lazada.ListOptions{Limit: 50})
 	require.NoError(t, err)
 	assert.Len(t, br, 50)
@@ -38,7 +44,7 @@ func TestListOptions(t *testing.T) {
 }
 
 func TestCategoryTree(t *testing.T) {
-	c := lazada.NewClient(AppKey, AppSecret, lazada.Singapore)
+	c := newClient(t, lazada.Singapore)
 	br, err := c.Products.CategoryTree(context.Background())
 	require.NoError(t, err)
 	assert.NotEmpty(t, br)
@@ -46,20 +52,20 @@ func TestCategoryTree(t *testing.T) {
 
 func TestCode(t *testing.T) {
 	t.SkipNow()
-	c := lazada.NewClient(AppKey, AppSecret, lazada.Singapore)
+	c := newClient(t, lazada.Singapore)
 	_, err := c.Auth.Exchange(context.Background(), "")
 	require.NoError(t, err)
 }
 
 func TestRefresh(t *testing.T) {
 	t.SkipNow()
-	c := lazada.NewClient(AppKey, AppSecret, lazada.Singapore)
+	c := newClient(t, lazada.Singapore)
 	_, err := c.Auth.Refresh(context.Background(), "50001500f08BWVnreeai1ffde6e2Tekwfrxk6eFpBqrzHKdtH1izvoEZQDQrd")
 	require.NoError(t, err)
 }
 
 func TestCategoryAttributes(t *testing.T) {
-	c := lazada.NewClient(AppKey, AppSecret, lazada.Singapore)
+	c := newClient(t, lazada.Singapore)
 	br, err := c.Products.CategoryAttributes(context.Background(), 10001996)
 	require.NoError(t, err)
 	assert.NotEmpty(t, br)
@@ -67,7 +73,7 @@ func TestCategoryAttributes(t *testing.T) {
 
 func TestImageMigrate(t *testing.T) {
 	t.SkipNow()
-	cl := lazada.NewClient(AppKey, AppSecret, lazada.Singapore)
+	cl := newClient(t, lazada.Singapore)
 	c := cl.NewTokenClient("")
 
 	resp, err := c.Products.MigrateImage(context.Background(), "https://images.unsplash.com/photo-1539784257995-d70d6089a5c8?ixlib=rb-0.3.5&ixid=eyJhcHBfaWQiOjEyMDd9&s=898c8ca83eebb1022781d179e39bb44a&auto=format&fit=crop&w=2134&q=80")
@@ -77,7 +83,7 @@ func TestImageMigrate(t *testing.T) {
 
 func TestCreateProduct(t *testing.T) {
 	t.SkipNow()
-	cl := lazada.NewClient(AppKey, AppSecret, lazada.Malaysia)
+	cl := newClient(t, lazada.Malaysia)
 	c := cl.NewTokenClient("")
 
 	product := &lazada.Product{
@@ -122,7 +128,7 @@ func TestCreateProduct(t *testing.T) {
 
 func TestGetProduct(t *testing.T) {
 	t.SkipNow()
-	cl := lazada.NewClient(AppKey, AppSecret, lazada.Malaysia)
+	cl := newClient(t, lazada.Malaysia)
 	c := cl.NewTokenClient("")
 
 	out := lazada.SliceString([]string{"16016131915889"})